@@ -0,0 +1,195 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/SxxAq/go-api/internal/config"
+)
+
+// runConfig implements `go-api config validate|print|schema|docs`.
+func runConfig(args []string) int {
+	fs := newFlagSet("config", "validate|print|schema|docs",
+		"validate  load the configuration and report any problem\n"+
+			"print     write the effective configuration to stdout, secrets redacted\n"+
+			"schema    write the JSON Schema for config files\n"+
+			"docs      write the Markdown reference of every setting")
+	format := fs.String("format", "yaml", "print: output format, yaml or json")
+	explain := fs.Bool("explain", false, "print: annotate each setting with where its value came from")
+	output := fs.String("o", "", "schema, docs: write to this file instead of stdout")
+	check := fs.Bool("check", false, "schema, docs: exit 1 if the -o file is out of date instead of writing it")
+	if code, ok := parseFlags(fs, args, 1); !ok {
+		return code
+	}
+
+	action := fs.Arg(0)
+	switch action {
+	case "schema":
+		b, err := config.JSONSchema()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "cannot generate schema: %s\n", err.Error())
+			return exitFailure
+		}
+		return writeGenerated(b, *output, *check)
+	case "docs":
+		return writeGenerated(config.Markdown(), *output, *check)
+	case "validate", "print":
+	default:
+		fs.Usage()
+		return exitUsage
+	}
+
+	if *format != "yaml" && *format != "json" {
+		fs.Usage()
+		return exitUsage
+	}
+
+	if action == "validate" {
+		if _, err := loadConfig(fs); err != nil {
+			fmt.Fprintf(os.Stderr, "config is invalid: %s\n", err.Error())
+			return exitFailure
+		}
+		fmt.Println("config is valid")
+		return exitOK
+	}
+
+	// Print the values even if they fail validation (e.g. a storage_path
+	// that only exists in production); that is when they matter most
+	settings, invalid := config.Explain(config.WithFlagSet(fs))
+	if settings == nil {
+		fmt.Fprintf(os.Stderr, "config is invalid: %s\n", invalid.Error())
+		return exitFailure
+	}
+
+	var err error
+	if *format == "json" {
+		err = printSettingsJSON(os.Stdout, settings, *explain)
+	} else {
+		err = printSettingsYAML(os.Stdout, settings, *explain)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "cannot encode config: %s\n", err.Error())
+		return exitFailure
+	}
+
+	if invalid != nil {
+		fmt.Fprintf(os.Stderr, "config is invalid: %s\n", invalid.Error())
+		return exitFailure
+	}
+
+	return exitOK
+}
+
+// printSettingsYAML writes settings as a YAML document in declaration
+// order. With explain, each value carries a comment naming its source.
+func printSettingsYAML(w io.Writer, settings []config.Setting, explain bool) error {
+	root := &yaml.Node{Kind: yaml.MappingNode}
+
+	for _, s := range settings {
+		keys := strings.Split(s.Path, ".")
+		parent := root
+		for _, key := range keys[:len(keys)-1] {
+			parent = yamlSection(parent, key)
+		}
+
+		var value yaml.Node
+		if err := value.Encode(s.Value); err != nil {
+			return fmt.Errorf("%s: %w", s.Path, err)
+		}
+		if explain {
+			value.LineComment = s.Source.String()
+		}
+
+		parent.Content = append(parent.Content,
+			&yaml.Node{Kind: yaml.ScalarNode, Value: keys[len(keys)-1]},
+			&value,
+		)
+	}
+
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(2)
+	if err := enc.Encode(root); err != nil {
+		return err
+	}
+
+	return enc.Close()
+}
+
+// yamlSection returns the mapping stored under key in parent, adding it
+// if needed.
+func yamlSection(parent *yaml.Node, key string) *yaml.Node {
+	for i := 0; i < len(parent.Content); i += 2 {
+		if parent.Content[i].Value == key {
+			return parent.Content[i+1]
+		}
+	}
+
+	section := &yaml.Node{Kind: yaml.MappingNode}
+	parent.Content = append(parent.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: key}, section)
+	return section
+}
+
+// printSettingsJSON writes settings as nested JSON objects. With explain,
+// each leaf becomes {"value": ..., "source": ...}.
+func printSettingsJSON(w io.Writer, settings []config.Setting, explain bool) error {
+	root := map[string]any{}
+
+	for _, s := range settings {
+		keys := strings.Split(s.Path, ".")
+		parent := root
+		for _, key := range keys[:len(keys)-1] {
+			section, ok := parent[key].(map[string]any)
+			if !ok {
+				section = map[string]any{}
+				parent[key] = section
+			}
+			parent = section
+		}
+
+		var value any = s.Value
+		if explain {
+			value = map[string]any{"value": s.Value, "source": s.Source.String()}
+		}
+		parent[keys[len(keys)-1]] = value
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(root)
+}
+
+// writeGenerated writes a generated file to path, or stdout if path is
+// empty. With check it only compares, so CI can fail when the checked-in
+// file has drifted from the Config struct.
+func writeGenerated(b []byte, path string, check bool) int {
+	if check {
+		if path == "" {
+			fmt.Fprintln(os.Stderr, "-check needs -o")
+			return exitUsage
+		}
+		current, err := os.ReadFile(path)
+		if err != nil || !bytes.Equal(current, b) {
+			fmt.Fprintf(os.Stderr, "%s is out of date, run `go generate ./internal/config`\n", path)
+			return exitFailure
+		}
+		return exitOK
+	}
+
+	if path == "" {
+		os.Stdout.Write(b)
+		return exitOK
+	}
+
+	if err := os.WriteFile(path, b, 0o644); err != nil {
+		fmt.Fprintf(os.Stderr, "cannot write %s: %s\n", path, err.Error())
+		return exitFailure
+	}
+
+	return exitOK
+}
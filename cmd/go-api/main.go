@@ -1,7 +1,162 @@
+// Command go-api is the students API server and its operational tooling.
+//
+// Usage:
+//
+//	go-api <command> [flags] [arguments]
+//
+// Run `go-api help` for the list of commands. Running go-api without a
+// command (or with only flags) starts the server, as `go-api serve` does.
 package main
 
-import "fmt"
+import (
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+
+	"github.com/SxxAq/go-api/internal/config"
+	"github.com/SxxAq/go-api/internal/logger"
+)
+
+// Process exit codes. exitDrainTimeout lets orchestrators tell a clean stop
+// apart from one that had to abandon in-flight requests.
+const (
+	exitOK           = 0
+	exitFailure      = 1
+	exitUsage        = 2
+	exitDrainTimeout = 3
+)
+
+// command is a go-api subcommand.
+type command struct {
+	name    string
+	summary string
+	run     func(args []string) int
+}
+
+// commands lists the subcommands in the order `go-api help` shows them.
+var commands = []command{
+	{"serve", "start the HTTP API", runServe},
+	{"migrate", "apply or roll back schema migrations", runMigrate},
+	{"config", "validate or print the effective configuration", runConfig},
+	{"seed", "insert sample students", runSeed},
+	{"version", "print version information", runVersion},
+}
 
 func main() {
-	fmt.Println("this is a api built in go for learning purpose ")
+	os.Exit(dispatch(os.Args[1:]))
+}
+
+// dispatch runs the subcommand named by args[0] and returns its exit code.
+func dispatch(args []string) int {
+	// Keep `go-api -config x` working as shorthand for `go-api serve -config x`
+	if len(args) == 0 || strings.HasPrefix(args[0], "-") {
+		return runServe(args)
+	}
+
+	name := args[0]
+	if name == "help" {
+		if len(args) > 1 {
+			// Per-command help is the command's own -h output
+			return dispatch([]string{args[1], "-h"})
+		}
+		usage(os.Stdout)
+		return exitOK
+	}
+
+	for _, c := range commands {
+		if c.name == name {
+			return c.run(args[1:])
+		}
+	}
+
+	fmt.Fprintf(os.Stderr, "go-api: unknown command %q\n\n", name)
+	usage(os.Stderr)
+	return exitUsage
+}
+
+// usage prints the top-level help.
+func usage(w io.Writer) {
+	fmt.Fprintln(w, "Usage: go-api <command> [flags] [arguments]")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Commands:")
+	for _, c := range commands {
+		fmt.Fprintf(w, "  %-8s %s\n", c.name, c.summary)
+	}
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Run `go-api help <command>` or `go-api <command> -h` for details.")
+	fmt.Fprintln(w, "Every command reads its config from CONFIG_PATH or -config, or without")
+	fmt.Fprintln(w, "a file from defaults and GOAPI_* environment variables.")
+}
+
+// newFlagSet returns the flag set for a subcommand with -config registered.
+// synopsis follows the command name in the usage line; description is
+// printed below it.
+func newFlagSet(name, synopsis, description string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	config.RegisterFlags(fs)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: go-api %s [flags] %s\n\n%s\n\nFlags:\n", name, synopsis, description)
+		fs.PrintDefaults()
+	}
+
+	return fs
+}
+
+// parseFlags parses args into fs, allowing flags before and after the
+// positional arguments, and checks that exactly nargs positional arguments
+// remain (nargs < 0 means any number). When the command should stop it
+// returns false together with the exit code: exitOK for -h, exitUsage for
+// bad flags or arguments.
+func parseFlags(fs *flag.FlagSet, args []string, nargs int) (int, bool) {
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			if err == flag.ErrHelp {
+				return exitOK, false
+			}
+			return exitUsage, false
+		}
+
+		// flag stops at the first non-flag argument; keep it and carry on
+		args = fs.Args()
+		if len(args) == 0 {
+			break
+		}
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+
+	if nargs >= 0 && len(positional) != nargs {
+		fs.Usage()
+		return exitUsage, false
+	}
+
+	// Leave the positional arguments where fs.Arg and fs.NArg can see them
+	fs.Parse(append([]string{"--"}, positional...))
+
+	return exitOK, true
+}
+
+// loadConfig loads the configuration using the -config flag of fs and the
+// CONFIG_PATH environment variable, as every subcommand does. With neither
+// set it falls back to defaults and GOAPI_* environment variables.
+func loadConfig(fs *flag.FlagSet) (*config.Config, error) {
+	return config.Load(config.WithFlagSet(fs))
+}
+
+// setupLogger builds the logger described by cfg.Logging and makes it the
+// slog default, so packages falling back to slog.Default() and output of
+// the standard log package end up in the same place. Until it is called,
+// commands log with slog's built-in default.
+func setupLogger(cfg *config.Config) (*logger.Logger, error) {
+	log, err := logger.New(cfg.Logging, cfg.Env)
+	if err != nil {
+		return nil, err
+	}
+	slog.SetDefault(log.Logger)
+
+	return log, nil
 }
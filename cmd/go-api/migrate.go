@@ -0,0 +1,139 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"strconv"
+	"text/tabwriter"
+
+	"github.com/SxxAq/go-api/internal/storage/migrate"
+	"github.com/SxxAq/go-api/internal/storage/sqlite"
+)
+
+// runMigrate implements `go-api migrate [flags] up|down|status|to N`.
+func runMigrate(args []string) int {
+	fs := newFlagSet("migrate", "up|down|status|to N",
+		"Apply all pending migrations (up), roll back the latest one (down),\n"+
+			"migrate to a specific version (to N, 0 reverts everything) or list\n"+
+			"migrations and when they were applied (status).")
+	if code, ok := parseFlags(fs, args, -1); !ok {
+		return code
+	}
+
+	// Check the arguments before touching storage, which creates the
+	// database file if it does not exist yet
+	action := fs.Arg(0)
+	switch nargs := fs.NArg(); {
+	case action == "up" || action == "down" || action == "status":
+		if nargs != 1 {
+			fs.Usage()
+			return exitUsage
+		}
+	case action == "to":
+		if nargs != 2 {
+			fs.Usage()
+			return exitUsage
+		}
+	default:
+		fs.Usage()
+		return exitUsage
+	}
+
+	var target int64
+	if action == "to" {
+		var err error
+		if target, err = strconv.ParseInt(fs.Arg(1), 10, 64); err != nil {
+			slog.Error("invalid version", "version", fs.Arg(1))
+			return exitUsage
+		}
+	}
+
+	cfg, err := loadConfig(fs)
+	if err != nil {
+		slog.Error("cannot load config", "err", err)
+		return exitFailure
+	}
+
+	log, err := setupLogger(cfg)
+	if err != nil {
+		slog.Error("cannot set up logging", "err", err)
+		return exitFailure
+	}
+	defer log.Close()
+
+	store, err := sqlite.New(cfg)
+	if err != nil {
+		log.Error("cannot open storage", "err", err)
+		return exitFailure
+	}
+	defer store.Close()
+
+	m, err := store.Migrator()
+	if err != nil {
+		log.Error("cannot load migrations", "err", err)
+		return exitFailure
+	}
+
+	ctx := context.Background()
+
+	var ran []migrate.Migration
+	switch action {
+	case "up":
+		ran, err = m.Up(ctx)
+	case "down":
+		ran, err = m.Down(ctx)
+	case "to":
+		ran, err = m.To(ctx, target)
+	case "status":
+		return printMigrationStatus(ctx, m, log.Logger)
+	}
+
+	if err != nil {
+		log.Error("migration failed", "err", err)
+		return exitFailure
+	}
+
+	logMigrations(log.Logger, ran)
+	version, err := m.Version(ctx)
+	if err != nil {
+		log.Error("cannot read schema version", "err", err)
+		return exitFailure
+	}
+	log.Info("schema migrated", "version", version, "latest", m.Latest())
+
+	return exitOK
+}
+
+// printMigrationStatus writes a table of known migrations to stdout.
+func printMigrationStatus(ctx context.Context, m *migrate.Migrator, log *slog.Logger) int {
+	statuses, err := m.Status(ctx)
+	if err != nil {
+		log.Error("cannot read migration status", "err", err)
+		return exitFailure
+	}
+
+	tw := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "VERSION\tNAME\tAPPLIED AT")
+	for _, s := range statuses {
+		appliedAt := "pending"
+		if s.Applied {
+			appliedAt = s.AppliedAt.Format("2006-01-02 15:04:05")
+		}
+		fmt.Fprintf(tw, "%d\t%s\t%s\n", s.Version, s.Name, appliedAt)
+	}
+
+	if err := tw.Flush(); err != nil {
+		return exitFailure
+	}
+
+	return exitOK
+}
+
+// logMigrations logs each migration that was run.
+func logMigrations(log *slog.Logger, ran []migrate.Migration) {
+	for _, mig := range ran {
+		log.Info("ran migration", "version", mig.Version, "name", mig.Name)
+	}
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/SxxAq/go-api/internal/storage/sqlite"
+	"github.com/SxxAq/go-api/internal/types"
+)
+
+// sampleStudents is the data inserted by `go-api seed`.
+var sampleStudents = []types.Student{
+	{Name: "Aarav Sharma", Email: "aarav.sharma@example.com", Age: 19},
+	{Name: "Priya Patel", Email: "priya.patel@example.com", Age: 21},
+	{Name: "Rohan Mehta", Email: "rohan.mehta@example.com", Age: 20},
+	{Name: "Sara Khan", Email: "sara.khan@example.com", Age: 22},
+	{Name: "Vikram Rao", Email: "vikram.rao@example.com", Age: 23},
+}
+
+// runSeed implements `go-api seed`.
+func runSeed(args []string) int {
+	fs := newFlagSet("seed", "", "Insert sample students. The schema must already be migrated.\n"+
+		"By default nothing is inserted if students already exist.")
+	force := fs.Bool("force", false, "insert the samples even if students already exist")
+	if code, ok := parseFlags(fs, args, 0); !ok {
+		return code
+	}
+
+	cfg, err := loadConfig(fs)
+	if err != nil {
+		slog.Error("cannot load config", "err", err)
+		return exitFailure
+	}
+
+	log, err := setupLogger(cfg)
+	if err != nil {
+		slog.Error("cannot set up logging", "err", err)
+		return exitFailure
+	}
+	defer log.Close()
+
+	store, err := sqlite.New(cfg)
+	if err != nil {
+		log.Error("cannot open storage", "err", err)
+		return exitFailure
+	}
+	defer store.Close()
+
+	ctx := context.Background()
+
+	existing, err := store.GetStudents(ctx)
+	if err != nil {
+		log.Error("cannot list students (did you run `go-api migrate up`?)", "err", err)
+		return exitFailure
+	}
+	if len(existing) > 0 && !*force {
+		log.Info("storage already has students, skipping (use -force to seed anyway)", "count", len(existing))
+		return exitOK
+	}
+
+	for _, s := range sampleStudents {
+		if _, err := store.CreateStudent(ctx, s.Name, s.Email, s.Age); err != nil {
+			log.Error("cannot insert student", "email", s.Email, "err", err)
+			return exitFailure
+		}
+	}
+	log.Info("inserted sample students", "count", len(sampleStudents))
+
+	return exitOK
+}
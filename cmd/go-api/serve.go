@@ -0,0 +1,242 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"expvar"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/SxxAq/go-api/internal/config"
+	"github.com/SxxAq/go-api/internal/http/handlers/fallback"
+	"github.com/SxxAq/go-api/internal/http/handlers/health"
+	"github.com/SxxAq/go-api/internal/http/handlers/student"
+	"github.com/SxxAq/go-api/internal/http/middleware"
+	"github.com/SxxAq/go-api/internal/http/server"
+	"github.com/SxxAq/go-api/internal/storage/sqlite"
+)
+
+// configPollInterval is how often serve checks the config files for changes.
+const configPollInterval = 5 * time.Second
+
+// runServe implements `go-api serve`: it starts the API and blocks until it
+// has shut down, returning the process exit code.
+func runServe(args []string) int {
+	// 1. Parse command-line flags
+	fs := newFlagSet("serve", "", "Start the HTTP API and serve until SIGINT/SIGTERM.")
+	if code, ok := parseFlags(fs, args, 0); !ok {
+		return code
+	}
+
+	// 2. Load config and keep watching it for reloadable changes
+	watcher, err := config.NewWatcher(configPollInterval, config.WithFlagSet(fs))
+	if err != nil {
+		slog.Error("cannot load config", "err", err)
+		return exitFailure
+	}
+	cfg := watcher.Current()
+
+	log, err := setupLogger(cfg)
+	if err != nil {
+		slog.Error("cannot set up logging", "err", err)
+		return exitFailure
+	}
+	defer log.Close()
+
+	// 3. Open storage at cfg.StoragePath
+	store, err := sqlite.New(cfg)
+	if err != nil {
+		log.Error("cannot open storage", "err", err)
+		return exitFailure
+	}
+	log.Info("storage initialized", "path", cfg.StoragePath)
+
+	if cfg.AutoMigrate {
+		if err := autoMigrate(store, log.Logger); err != nil {
+			log.Error("cannot migrate storage", "err", err)
+			store.Close()
+			return exitFailure
+		}
+	}
+
+	// 4. Setup readiness checks: storage answers and its schema is current
+	migrator, err := store.Migrator()
+	if err != nil {
+		log.Error("cannot load migrations", "err", err)
+		store.Close()
+		return exitFailure
+	}
+
+	checker := health.NewChecker()
+	checker.Add("storage", store.Ping)
+	checker.Add("migrations", func(ctx context.Context) error {
+		version, err := migrator.Version(ctx)
+		if err != nil {
+			return err
+		}
+		if version != migrator.Latest() {
+			return fmt.Errorf("schema at version %d, want %d (run `go-api migrate up`)", version, migrator.Latest())
+		}
+		return nil
+	})
+
+	// 5. Setup router; the /api routes need auth.api_key when it is set
+	auth := middleware.RequireBearer(cfg.Auth.APIKey.Value())
+	router := http.NewServeMux()
+	router.HandleFunc("GET /{$}", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("Welcome to go-api"))
+	})
+	router.HandleFunc("GET /healthz", health.Live())
+	router.HandleFunc("GET /readyz", health.Ready(checker, log.Logger))
+	router.Handle("POST /api/students", auth(student.New(store, log.Logger)))
+	router.Handle("GET /api/students", auth(student.GetList(store, log.Logger)))
+	router.Handle("GET /api/students/{id}", auth(student.GetById(store, log.Logger)))
+	router.Handle("PUT /api/students/{id}", auth(student.Update(store, log.Logger)))
+	router.Handle("PATCH /api/students/{id}", auth(student.Patch(store, log.Logger)))
+	router.Handle("DELETE /api/students/{id}", auth(student.Delete(store, log.Logger)))
+	router.HandleFunc("/", fallback.Handler(router)) // JSON 404/405 for everything else
+
+	// 6. Setup server
+	srv, err := server.New(cfg.HttpServer, router, log.Logger)
+	if err != nil {
+		log.Error("cannot setup server", "err", err)
+		store.Close()
+		return exitFailure
+	}
+
+	// 7. Serve expvar counters such as http_panics on the internal debug
+	//    listener, if configured; they must not be reachable publicly. Bind
+	//    it up front so a taken port fails startup like the main listener
+	var debugSrv *http.Server
+	if addr := cfg.HttpServer.DebugAddr; addr != "" {
+		ln, err := net.Listen("tcp", addr)
+		if err != nil {
+			log.Error("cannot start debug server", "err", err)
+			store.Close()
+			return exitFailure
+		}
+
+		debugMux := http.NewServeMux()
+		debugMux.Handle("GET /debug/vars", expvar.Handler())
+		debugSrv = &http.Server{Addr: addr, Handler: debugMux, ReadHeaderTimeout: cfg.HttpServer.ReadHeaderTimeout}
+
+		go func() {
+			log.Info("debug server started", "addr", addr)
+			if err := debugSrv.Serve(ln); !errors.Is(err, http.ErrServerClosed) {
+				log.Error("debug server failed", "err", err)
+			}
+		}()
+	}
+
+	// 8. Serve in the background until we receive SIGINT/SIGTERM
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	watcher.Subscribe(func(c *config.Config) {
+		srv.SetMaxBodyBytes(int64(c.HttpServer.MaxBodyBytes))
+		if err := log.SetLevel(c.Logging.Level); err != nil {
+			log.Error("cannot change log level", "err", err)
+		}
+	})
+	go watcher.Run(ctx)
+
+	serveErr := make(chan error, 1)
+	go func() {
+		log.Info("server started", "addr", srv.Addr, "env", cfg.Env, "tls", srv.TLS())
+		serveErr <- srv.ListenAndServe()
+	}()
+
+	// 9. Reload TLS certificates from disk on SIGHUP
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	defer signal.Stop(hup)
+
+	go func() {
+		for range hup {
+			if err := srv.ReloadCertificates(); err != nil {
+				log.Error("failed to reload certificates", "err", err)
+				continue
+			}
+			if srv.TLS() {
+				log.Info("certificates reloaded")
+			}
+		}
+	}()
+
+	select {
+	case err := <-serveErr:
+		log.Error("failed to start server", "err", err)
+		if debugSrv != nil {
+			debugSrv.Close()
+		}
+		store.Close()
+		return exitFailure
+	case <-ctx.Done():
+	}
+	stop() // A second signal now kills the process immediately
+
+	// 10. Fail readiness first and give load balancers shutdown_delay to
+	//     notice, while still serving whatever they send meanwhile
+	checker.Drain()
+	if delay := cfg.HttpServer.ShutdownDelay; delay > 0 {
+		log.Info("readiness is failing, waiting before closing the listener", "delay", delay)
+		time.Sleep(delay)
+	}
+
+	// 11. Stop accepting connections and drain in-flight requests
+	log.Info("shutting down the server", "timeout", cfg.HttpServer.ShutdownTimeout)
+
+	code := exitOK
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.HttpServer.ShutdownTimeout)
+	defer cancel()
+
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		if errors.Is(err, context.DeadlineExceeded) {
+			log.Warn("drain timeout exceeded, closing remaining connections")
+			code = exitDrainTimeout
+		} else {
+			log.Error("failed to shutdown server", "err", err)
+			code = exitFailure
+		}
+		srv.Close()
+	}
+	if debugSrv != nil {
+		debugSrv.Close()
+	}
+
+	// 12. Close storage only after in-flight requests are done with it
+	if err := store.Close(); err != nil {
+		log.Error("failed to close storage", "err", err)
+		if code == exitOK {
+			code = exitFailure
+		}
+	}
+
+	if code == exitOK {
+		log.Info("server shutdown successfully")
+	}
+
+	return code
+}
+
+// autoMigrate applies pending schema migrations before serving.
+func autoMigrate(store *sqlite.Sqlite, log *slog.Logger) error {
+	m, err := store.Migrator()
+	if err != nil {
+		return err
+	}
+
+	ran, err := m.Up(context.Background())
+	if err != nil {
+		return err
+	}
+	logMigrations(log, ran)
+
+	return nil
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"fmt"
+	"runtime"
+	"runtime/debug"
+)
+
+// Build metadata, overridable at link time:
+//
+//	go build -ldflags "-X main.version=v1.2.3 -X main.commit=$(git rev-parse HEAD)"
+var (
+	version = "dev"
+	commit  = ""
+)
+
+// runVersion implements `go-api version`.
+func runVersion(args []string) int {
+	fs := newFlagSet("version", "", "Print the version, commit and Go toolchain of this binary.")
+	if code, ok := parseFlags(fs, args, 0); !ok {
+		return code
+	}
+
+	rev := commit
+	if rev == "" {
+		rev = vcsRevision()
+	}
+	if rev == "" {
+		rev = "unknown"
+	}
+
+	fmt.Printf("go-api %s (commit %s, %s %s/%s)\n", version, rev, runtime.Version(), runtime.GOOS, runtime.GOARCH)
+	return exitOK
+}
+
+// vcsRevision returns the commit recorded by the Go toolchain, if any.
+func vcsRevision() string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return ""
+	}
+
+	for _, s := range info.Settings {
+		if s.Key == "vcs.revision" {
+			return s.Value
+		}
+	}
+
+	return ""
+}
@@ -3,9 +3,11 @@ module github.com/SxxAq/go-api
 go 1.24.6
 
 require (
-	github.com/BurntSushi/toml v1.5.0 // indirect
-	github.com/ilyakaznacheev/cleanenv v1.5.0 // indirect
-	github.com/joho/godotenv v1.5.1 // indirect
-	gopkg.in/yaml.v3 v3.0.1 // indirect
-	olympos.io/encoding/edn v0.0.0-20201019073823-d3554ca0b0a3 // indirect
+	github.com/BurntSushi/toml v1.5.0
+	github.com/ilyakaznacheev/cleanenv v1.5.0
+	github.com/joho/godotenv v1.5.1
+	github.com/mattn/go-sqlite3 v1.14.52
+	gopkg.in/yaml.v3 v3.0.1
 )
+
+require olympos.io/encoding/edn v0.0.0-20201019073823-d3554ca0b0a3 // indirect
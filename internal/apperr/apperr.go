@@ -0,0 +1,93 @@
+// Package apperr defines the typed errors shared by storage and the HTTP
+// handlers. Each carries a Code that internal/http/response maps to an
+// HTTP status, so storage can report "not found" or "conflict" without
+// knowing about HTTP, and every endpoint fails with the same body.
+package apperr
+
+import (
+	"fmt"
+	"maps"
+)
+
+// Code classifies an Error. It is sent to clients as error.code.
+type Code string
+
+const (
+	CodeBadRequest       Code = "bad_request"        // Malformed request, e.g. invalid JSON
+	CodeValidation       Code = "validation_failed"  // Well-formed but invalid values, see Error.Fields
+	CodeUnauthorized     Code = "unauthorized"       // Missing or bad credentials
+	CodeNotFound         Code = "not_found"          // The resource does not exist
+	CodeMethodNotAllowed Code = "method_not_allowed" // The path exists, but not for this method
+	CodeConflict         Code = "conflict"           // Clashes with existing data, e.g. a duplicate
+	CodeTooLarge         Code = "payload_too_large"  // Request body over the configured limit
+	CodeInternal         Code = "internal"           // Anything unexpected; details are only logged
+)
+
+// Error is an error with a Code and a message that is safe to show to
+// clients. The underlying cause, if any, is only reachable through Unwrap.
+type Error struct {
+	Code    Code
+	Message string
+	Fields  map[string]string // Per-field messages keyed by JSON field name, for CodeValidation
+	Err     error             // Underlying cause, never shown to clients
+}
+
+// New returns an Error with the given code and formatted message.
+func New(code Code, format string, args ...any) *Error {
+	return &Error{Code: code, Message: fmt.Sprintf(format, args...)}
+}
+
+// BadRequest returns a CodeBadRequest Error.
+func BadRequest(format string, args ...any) *Error {
+	return New(CodeBadRequest, format, args...)
+}
+
+// Validation returns a CodeValidation Error listing the invalid fields.
+func Validation(fields map[string]string) *Error {
+	return &Error{Code: CodeValidation, Message: "validation failed", Fields: fields}
+}
+
+// Unauthorized returns a CodeUnauthorized Error.
+func Unauthorized(format string, args ...any) *Error {
+	return New(CodeUnauthorized, format, args...)
+}
+
+// NotFound returns a CodeNotFound Error.
+func NotFound(format string, args ...any) *Error {
+	return New(CodeNotFound, format, args...)
+}
+
+// Conflict returns a CodeConflict Error.
+func Conflict(format string, args ...any) *Error {
+	return New(CodeConflict, format, args...)
+}
+
+func (e *Error) Error() string {
+	if e.Err != nil {
+		return e.Message + ": " + e.Err.Error()
+	}
+
+	return e.Message
+}
+
+// Unwrap returns the underlying cause.
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Is reports whether target is an *Error with the same Code, so sentinels
+// such as storage.ErrNotFound match every error of their kind whatever
+// the message.
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	return ok && t.Code == e.Code
+}
+
+// Wrap returns a copy of e with err as its underlying cause.
+func (e *Error) Wrap(err error) *Error {
+	c := *e
+	c.Fields = maps.Clone(e.Fields)
+	c.Err = err
+
+	return &c
+}
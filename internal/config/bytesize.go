@@ -0,0 +1,76 @@
+package config
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// ByteSize is a size in bytes that can be written in config files and
+// environment variables either as a plain integer ("1048576") or with a
+// unit suffix ("512KiB", "1MB"). Decimal units (KB, MB, GB) are powers of
+// 1000, binary units (KiB, MiB, GiB) powers of 1024.
+type ByteSize int64
+
+// byteUnits maps accepted suffixes to their multiplier. Longer suffixes are
+// listed first so "MiB" is not mistaken for "B".
+var byteUnits = []struct {
+	suffix string
+	size   int64
+}{
+	{"KiB", 1 << 10},
+	{"MiB", 1 << 20},
+	{"GiB", 1 << 30},
+	{"KB", 1000},
+	{"MB", 1000 * 1000},
+	{"GB", 1000 * 1000 * 1000},
+	{"B", 1},
+}
+
+// UnmarshalText parses a size such as "1MiB". It is used by both the YAML
+// decoder and cleanenv when reading environment variables.
+func (b *ByteSize) UnmarshalText(text []byte) error {
+	s := strings.TrimSpace(string(text))
+
+	mult := int64(1)
+	for _, u := range byteUnits {
+		if strings.HasSuffix(s, u.suffix) {
+			s = strings.TrimSpace(strings.TrimSuffix(s, u.suffix))
+			mult = u.size
+			break
+		}
+	}
+
+	n, err := strconv.ParseInt(s, 10, 64)
+	if err != nil || n < 0 {
+		return fmt.Errorf("invalid byte size %q", string(text))
+	}
+	if n > math.MaxInt64/mult {
+		return fmt.Errorf("byte size %q is too large", string(text))
+	}
+
+	*b = ByteSize(n * mult)
+	return nil
+}
+
+// String formats the size using the largest binary unit that divides it
+// exactly, so values round-trip through UnmarshalText.
+func (b ByteSize) String() string {
+	for _, u := range []struct {
+		suffix string
+		size   int64
+	}{{"GiB", 1 << 30}, {"MiB", 1 << 20}, {"KiB", 1 << 10}} {
+		if b != 0 && int64(b)%u.size == 0 {
+			return strconv.FormatInt(int64(b)/u.size, 10) + u.suffix
+		}
+	}
+
+	return strconv.FormatInt(int64(b), 10) + "B"
+}
+
+// MarshalText formats the size like String, so printed configs stay
+// readable and can be loaded back.
+func (b ByteSize) MarshalText() ([]byte, error) {
+	return []byte(b.String()), nil
+}
@@ -0,0 +1,49 @@
+package config
+
+import (
+	"math"
+	"testing"
+)
+
+func TestByteSize(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    ByteSize
+		str     string
+		wantErr bool
+	}{
+		{"0", 0, "0B", false},
+		{"1048576", 1 << 20, "1MiB", false},
+		{"1MiB", 1 << 20, "1MiB", false},
+		{"512KiB", 512 << 10, "512KiB", false},
+		{" 2 GiB ", 2 << 30, "2GiB", false},
+		{"1MB", 1000 * 1000, "1000000B", false},
+		{"1500", 1500, "1500B", false},
+		{"9223372036854775807", math.MaxInt64, "9223372036854775807B", false},
+		{"8589934591GiB", 8589934591 << 30, "8589934591GiB", false},
+		{"8589934592GiB", 0, "", true}, // 2^63 bytes overflows int64
+		{"9223372036854775808", 0, "", true},
+		{"-1", 0, "", true},
+		{"1TiB", 0, "", true},
+		{"MiB", 0, "", true},
+		{"", 0, "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			var b ByteSize
+			err := b.UnmarshalText([]byte(tt.in))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("UnmarshalText(%q) error = %v, wantErr %t", tt.in, err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if b != tt.want {
+				t.Errorf("UnmarshalText(%q) = %d, want %d", tt.in, int64(b), int64(tt.want))
+			}
+			if got := b.String(); got != tt.str {
+				t.Errorf("String() = %q, want %q", got, tt.str)
+			}
+		})
+	}
+}
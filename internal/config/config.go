@@ -1,65 +1,382 @@
 // Package config handles loading application configuration from YAML files
 // and environment variables using the cleanenv library.
+//
+// Every setting can come from four places. Later sources override earlier
+// ones:
+//
+//  1. defaults from env-default struct tags
+//  2. the config file (CONFIG_PATH or -config), if any; a file named
+//     base.yaml is deep-merged with the <env>.yaml overlay next to it, see Load
+//  3. environment variables, named GOAPI_ followed by the upper-cased YAML
+//     path, e.g. GOAPI_HTTP_SERVER_ADDR for http_server.addr
+//  4. command-line flags named after the YAML path, e.g. -http_server.addr
+//
+// New fields must carry an env tag (and nested sections an env-prefix tag)
+// following the same scheme so they stay reachable from the environment.
+// Fields that the running process can pick up without a restart are also
+// tagged reload:"true"; see Watcher.
 package config
 
 import (
-	"flag" // For parsing command-line flags
-	"log"  // For logging errors and exiting program
-	"os"   // For accessing environment variables and checking file existence
+	"errors"   // For declaring sentinel errors
+	"flag"     // For parsing command-line flags
+	"fmt"      // For wrapping errors with context
+	"io/fs"    // For matching "file does not exist" errors
+	"log/slog" // For reporting warnings and fatal load errors
+	"os"       // For accessing environment variables and checking file existence
+	"time"     // For duration-valued settings
 
-	"github.com/ilyakaznacheev/cleanenv" // Third-party package for config parsing
+	"github.com/ilyakaznacheev/cleanenv" // Third-party package for env parsing
+)
+
+// Sentinel errors returned by Load. Match them with errors.Is; the returned
+// error wraps the sentinel together with the underlying cause.
+var (
+	// ErrPathNotSet means RequireFile was given but no option, CONFIG_PATH
+	// or -config provided a path.
+	ErrPathNotSet = errors.New("config path is not set")
+	// ErrFileNotFound means the resolved config file does not exist.
+	ErrFileNotFound = errors.New("config file does not exist")
+	// ErrParse means the config file could not be decoded.
+	ErrParse = errors.New("cannot parse config file")
+	// ErrValidation means the decoded config is missing or has invalid values.
+	ErrValidation = errors.New("invalid config")
+	// ErrUnknownKey means a config file has keys that match no setting. It
+	// is only returned with UnknownKeysFail, wrapped together with ErrParse.
+	ErrUnknownKey = errors.New("unknown config key")
 )
 
 // HttpServer holds HTTP server-specific configuration.
+// The timeouts and limits default to safe non-zero values so a slow or
+// malicious client cannot hold a connection open forever.
 type HttpServer struct {
-	Addr string `yaml:"addr"` // Maps to 'addr' key in YAML
+	Addr              string        `yaml:"addr" env:"ADDR" env-default:":8080" env-description:"host:port the server listens on"`
+	DebugAddr         string        `yaml:"debug_addr" env:"DEBUG_ADDR" env-description:"host:port of an internal listener serving expvar counters at /debug/vars; empty disables it. Keep it off public interfaces"`
+	ShutdownTimeout   time.Duration `yaml:"shutdown_timeout" env:"SHUTDOWN_TIMEOUT" env-default:"10s" env-description:"How long to drain in-flight requests on shutdown"`
+	ShutdownDelay     time.Duration `yaml:"shutdown_delay" env:"SHUTDOWN_DELAY" env-description:"How long /readyz reports 503 before the listener closes on shutdown, so load balancers stop routing first"`
+	ReadTimeout       time.Duration `yaml:"read_timeout" env:"READ_TIMEOUT" env-default:"15s" env-description:"Max time to read the whole request, body included"`
+	ReadHeaderTimeout time.Duration `yaml:"read_header_timeout" env:"READ_HEADER_TIMEOUT" env-default:"5s" env-description:"Max time to read request headers"`
+	WriteTimeout      time.Duration `yaml:"write_timeout" env:"WRITE_TIMEOUT" env-default:"30s" env-description:"Max time from end of headers to end of response"`
+	IdleTimeout       time.Duration `yaml:"idle_timeout" env:"IDLE_TIMEOUT" env-default:"60s" env-description:"Max time a keep-alive connection may sit idle"`
+	MaxHeaderBytes    ByteSize      `yaml:"max_header_bytes" env:"MAX_HEADER_BYTES" env-default:"1MiB" env-description:"Max size of request headers"`
+	MaxBodyBytes      ByteSize      `yaml:"max_body_bytes" env:"MAX_BODY_BYTES" env-default:"1MiB" reload:"true" env-description:"Max size of a request body"`
+	TLS               TLS           `yaml:"tls" env-prefix:"TLS_"` // Optional TLS / mutual-TLS settings
+}
+
+// TLS holds the server certificate and, for mutual TLS, the CA bundle used
+// to verify client certificates. TLS is enabled when CertFile is set.
+type TLS struct {
+	CertFile     string `yaml:"cert_file" env:"CERT_FILE" env-description:"PEM certificate (chain) presented to clients; enables HTTPS"`
+	KeyFile      string `yaml:"key_file" env:"KEY_FILE" env-description:"PEM private key for cert_file"`
+	ClientCAFile string `yaml:"client_ca_file" env:"CLIENT_CA_FILE" env-description:"PEM bundle of CAs trusted to sign client certificates"`
+	MinVersion   string `yaml:"min_version" env:"MIN_VERSION" env-default:"1.2" enum:"1.0,1.1,1.2,1.3" env-description:"Lowest TLS version accepted"`
+	ClientAuth   string `yaml:"client_auth" env:"CLIENT_AUTH" enum:",none,request,require,verify_if_given,require_and_verify" env-description:"Client certificate policy; defaults to require_and_verify with client_ca_file, none without"`
+}
+
+// Enabled reports whether the server should serve HTTPS.
+func (t TLS) Enabled() bool {
+	return t.CertFile != ""
+}
+
+// Logging configures the application logger built by internal/logger. Empty
+// Level and Format take their defaults from Config.Env: debug-level text in
+// local, info-level JSON everywhere else.
+type Logging struct {
+	Level     string `yaml:"level" env:"LEVEL" enum:",debug,info,warn,error" reload:"true" env-description:"Lowest level logged; defaults to debug in local, info elsewhere"`
+	Format    string `yaml:"format" env:"FORMAT" enum:",text,json" env-description:"Log line format; defaults to text in local, json elsewhere"`
+	Output    string `yaml:"output" env:"OUTPUT" env-default:"stderr" env-description:"stderr, stdout or the path of a file to append to"`
+	AddSource bool   `yaml:"add_source" env:"ADD_SOURCE" env-description:"Include the source file and line of each log call"`
+}
+
+// Auth configures authentication of the /api routes. It is off unless
+// APIKey is set.
+type Auth struct {
+	APIKey Secret `yaml:"api_key" env:"API_KEY" env-description:"Bearer token clients must send in the Authorization header of /api requests; empty disables authentication. Accepts file: and env: references"`
 }
 
 // Config is the main application configuration struct.
-// It can be populated from a YAML file or environment variables.
+// It can be populated from a YAML file, environment variables or flags.
 type Config struct {
-	Env         string               `yaml:"env" env:"ENV" env-required:"true"` // Environment (e.g., dev, prod), required
-	StoragePath string               `yaml:"storage_path" env-required:"true"`  // Path for storing files, required
-	HttpServer  `yaml:"http_server"` // Embedded struct for HTTP server config
+	Env         string                                         `yaml:"env" env:"ENV" env-required:"true" enum:"local,dev,staging,prod" env-description:"Deployment environment"`
+	StoragePath string                                         `yaml:"storage_path" env:"STORAGE_PATH" env-required:"true" env-description:"Path of the SQLite database file"`
+	AutoMigrate bool                                           `yaml:"auto_migrate" env:"AUTO_MIGRATE" env-description:"Apply pending schema migrations on startup"`
+	HttpServer  `yaml:"http_server" env-prefix:"HTTP_SERVER_"` // Embedded struct for HTTP server config
+	Logging     Logging                                        `yaml:"logging" env-prefix:"LOGGING_"` // Application logger settings
+	Auth        Auth                                           `yaml:"auth" env-prefix:"AUTH_"`       // API authentication
+}
+
+// Option customises how Load resolves and reads the configuration.
+type Option func(*options)
+
+// options collects the settings applied by Option values.
+type options struct {
+	path        string         // Explicit config file path, skips env/flag lookup
+	flags       *flag.FlagSet  // Parsed flag set holding -config and the field flags, if any
+	requireFile bool           // Fail with ErrPathNotSet instead of running without a file
+	format      string         // File format, "" or FormatAuto to pick by extension
+	unknownKeys UnknownKeyMode // What to do with config file keys that match no setting, "" for the default
+	logger      *slog.Logger   // Destination of warnings and reload messages, nil for slog.Default
+	noValidate  bool           // Skip Validate, set by Explain which reports it separately
 }
 
-// MustLoad loads the configuration from environment variable, command-line flag, or YAML file.
-// It stops the program immediately if anything goes wrong (fail-fast pattern).
-func MustLoad() *Config {
-	var cfgPath string
+// RegisterFlags defines the -config flag and one override flag per setting
+// (e.g. -http_server.addr) on fs, so the caller can own argument parsing
+// and add its own flags next to them. Parse fs, then pass it to Load with
+// WithFlagSet.
+func RegisterFlags(fs *flag.FlagSet) {
+	fs.String("config", "", "path to the configuration file (env CONFIG_PATH)")
+	fs.String("config-format", FormatAuto, "config file format: auto (by extension), yaml, json, toml or env")
+	fs.String("config-unknown-keys", string(UnknownKeysWarn), "what to do with config file keys that match no setting: ignore, warn or fail")
+	fs.Bool("config-strict", false, "shorthand for -config-unknown-keys=fail")
+	registerFieldFlags(fs)
+}
 
-	// 1. Check if CONFIG_PATH environment variable is set
-	cfgPath = os.Getenv("CONFIG_PATH")
+// WithPath makes Load read the given file instead of resolving the path
+// from the -config flag or the CONFIG_PATH environment variable.
+func WithPath(path string) Option {
+	return func(o *options) {
+		o.path = path
+	}
+}
 
-	// 2. If not set, check for -config command-line flag
-	if cfgPath == "" {
-		// Define a command-line flag "config"
-		flags := flag.String("config", "", "path to the configuration file")
-		flag.Parse() // Parse all command-line flags
+// WithFlagSet makes Load take the config path from the -config flag of fs
+// and apply the field flags of fs on top of the file and environment. fs
+// must already be parsed; flags are looked up, never defined, so sets
+// without RegisterFlags are simply ignored.
+func WithFlagSet(fs *flag.FlagSet) Option {
+	return func(o *options) {
+		o.flags = fs
+	}
+}
 
-		cfgPath = *flags // Use flag value if provided
-		if cfgPath == "" {
-			// If neither ENV nor flag is set, stop program
-			log.Fatal("Config path is not set. Use CONFIG_PATH env or -config flag")
+// RequireFile makes Load fail with ErrPathNotSet when no config file is
+// given, instead of building the Config from defaults and environment
+// variables alone.
+func RequireFile() Option {
+	return func(o *options) {
+		o.requireFile = true
+	}
+}
+
+// WithFormat makes Load parse config files as format (FormatYAML,
+// FormatJSON, FormatTOML or FormatEnv) instead of picking the parser by
+// file extension. It overrides the -config-format flag.
+func WithFormat(format string) Option {
+	return func(o *options) {
+		o.format = format
+	}
+}
+
+// WithUnknownKeys sets what Load does when a config file contains keys
+// that match no setting, such as a misspelt section name: log a warning
+// with a "did you mean" suggestion (UnknownKeysWarn, the default), fail
+// with ErrUnknownKey (UnknownKeysFail) or ignore them. It overrides the
+// -config-unknown-keys and -config-strict flags.
+func WithUnknownKeys(mode UnknownKeyMode) Option {
+	return func(o *options) {
+		o.unknownKeys = mode
+	}
+}
+
+// Strict is shorthand for WithUnknownKeys(UnknownKeysFail).
+func Strict() Option {
+	return WithUnknownKeys(UnknownKeysFail)
+}
+
+// WithLogger makes Load and Watcher log warnings, such as unknown keys,
+// and reload messages to l instead of slog.Default().
+func WithLogger(l *slog.Logger) Option {
+	return func(o *options) {
+		o.logger = l
+	}
+}
+
+// Load loads the configuration from the YAML file, environment variables and
+// command-line flags, in that order of precedence (see the package doc).
+//
+// The file is parsed as YAML, JSON, TOML or dotenv (GOAPI_* assignments)
+// according to its extension or WithFormat / -config-format; all formats
+// decode to the same Config.
+//
+// If the config file is named base.yaml (or base.json, base.toml), Load
+// also reads <env>.yaml from the same directory, where <env> comes from the
+// -env flag, GOAPI_ENV or the env key of the base file, and deep-merges it
+// over the base: nested sections are merged key by key, other values are
+// replaced. A missing overlay is not an error.
+//
+// Without any config file (no option, -config or CONFIG_PATH), Load builds
+// the Config from env-default values, GOAPI_* environment variables and
+// flags alone, which suits twelve-factor container deployments. It then
+// only fails if a required value is still missing; pass RequireFile to
+// insist on a file.
+//
+// Unlike MustLoad it never exits; every failure is returned wrapping one of
+// ErrPathNotSet, ErrFileNotFound, ErrParse or ErrValidation.
+func Load(opts ...Option) (*Config, error) {
+	cfg, _, err := load(newOptions(opts))
+	return cfg, err
+}
+
+// newOptions applies opts to a zero options value.
+func newOptions(opts []Option) options {
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	return o
+}
+
+// load implements Load and also returns the config file layers that were
+// read, so a Watcher knows what to watch and Explain where values came from.
+func load(o options) (*Config, []layer, error) {
+	// 1. Resolve the config path: explicit option, then -config, then CONFIG_PATH
+	cfgPath := o.path
+	if cfgPath == "" && o.flags != nil {
+		if f := o.flags.Lookup("config"); f != nil {
+			cfgPath = f.Value.String()
 		}
 	}
+	if cfgPath == "" {
+		cfgPath = os.Getenv("CONFIG_PATH")
+	}
 
-	// 3. Check if the file exists
-	if _, err := os.Stat(cfgPath); os.IsNotExist(err) {
-		log.Fatalf("Config file does not exist: %s", cfgPath)
+	// 2. Read the file, plus the overlay for Env if it is a base file.
+	//    Without a file, defaults, environment and flags are all we use.
+	format, unknown := fileFlags(o)
+	mode, err := parseUnknownKeyMode(unknown)
+	if err != nil {
+		return nil, nil, err
+	}
+	var layers []layer
+	switch {
+	case cfgPath != "":
+		if layers, err = readConfigFile(cfgPath, overlayEnv(o.flags), format); err != nil {
+			return nil, nil, err
+		}
+		if err := checkUnknownKeys(layers, mode, o.log()); err != nil {
+			return nil, nil, err
+		}
+	case o.requireFile:
+		return nil, nil, fmt.Errorf("%w: use CONFIG_PATH env or -config flag", ErrPathNotSet)
 	}
 
-	// 4. Initialize an empty Config struct
+	// 3. Decode the merged layers into an empty Config struct
 	var cfg Config
+	if err := decodeMap(merged(layers), &cfg); err != nil {
+		return nil, nil, fmt.Errorf("%w %s: %w", ErrParse, cfgPath, err)
+	}
 
-	// 5. Use cleanenv to read YAML file and populate the struct
-	//    - Fields can also be overridden by environment variables
+	// 4. Apply flags, so they can satisfy env-required and suppress defaults
+	if o.flags != nil {
+		if err := applyFlags(o.flags, &cfg); err != nil {
+			return nil, nil, fmt.Errorf("%w: %w", ErrValidation, err)
+		}
+	}
+
+	// 5. Let cleanenv apply GOAPI_* environment overrides and defaults
 	//    - Fields marked with env-required:"true" must have values
-	err := cleanenv.ReadConfig(cfgPath, &cfg)
+	root := envRoot{Config: cfg}
+	if err := cleanenv.ReadEnv(&root); err != nil {
+		if cfgPath == "" {
+			return nil, nil, fmt.Errorf("%w: %w (no config file given; set CONFIG_PATH, -config or %s* variables)", ErrValidation, err, EnvPrefix)
+		}
+		return nil, nil, fmt.Errorf("%w: %w", ErrValidation, err)
+	}
+	cfg = root.Config
+
+	// 6. Apply flags again so they win over the environment
+	if o.flags != nil {
+		if err := applyFlags(o.flags, &cfg); err != nil {
+			return nil, nil, fmt.Errorf("%w: %w", ErrValidation, err)
+		}
+	}
+
+	// 7. Resolve file: and env: references in Secret fields
+	if err := resolveSecrets(&cfg); err != nil {
+		return nil, nil, fmt.Errorf("%w: %w", ErrValidation, err)
+	}
+
+	// 8. Check the values themselves
+	if o.noValidate {
+		return &cfg, layers, nil
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, nil, fmt.Errorf("%w: %w", ErrValidation, err)
+	}
+
+	return &cfg, layers, nil
+}
+
+// MustLoad is like Load but stops the program immediately if anything goes
+// wrong (fail-fast pattern), after logging the error to the logger given
+// by WithLogger or slog.Default().
+func MustLoad(opts ...Option) *Config {
+	cfg, err := Load(opts...)
 	if err != nil {
-		log.Fatalf("Cannot read config file: %s", err.Error())
+		newOptions(opts).log().Error("cannot load config", "err", err)
+		os.Exit(1)
+	}
+
+	return cfg
+}
+
+// readConfigFile checks that path exists and reads it together with its
+// overlay, see readLayers.
+func readConfigFile(path, env, format string) ([]layer, error) {
+	if _, err := os.Stat(path); err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, fmt.Errorf("%w: %s", ErrFileNotFound, path)
+		}
+		return nil, fmt.Errorf("stat config file %s: %w", path, err)
+	}
+
+	return readLayers(path, env, format)
+}
+
+// log returns the logger set by WithLogger, or the current slog default.
+func (o options) log() *slog.Logger {
+	if o.logger != nil {
+		return o.logger
+	}
+
+	return slog.Default()
+}
+
+// fileFlags returns the file format and unknown key mode to use: options
+// win, then the -config-format, -config-strict and -config-unknown-keys
+// flags.
+func fileFlags(o options) (format, unknownKeys string) {
+	format, unknownKeys = o.format, string(o.unknownKeys)
+	if o.flags == nil {
+		return format, unknownKeys
+	}
+
+	if f := o.flags.Lookup("config-format"); f != nil && format == "" {
+		format = f.Value.String()
+	}
+	if unknownKeys == "" {
+		if f := o.flags.Lookup("config-strict"); f != nil && f.Value.String() == "true" {
+			unknownKeys = string(UnknownKeysFail)
+		} else if f := o.flags.Lookup("config-unknown-keys"); f != nil {
+			unknownKeys = f.Value.String()
+		}
+	}
+
+	return format, unknownKeys
+}
+
+// overlayEnv returns the Env chosen outside the config file, from the -env
+// flag or GOAPI_ENV, which picks the overlay to merge over a base file.
+func overlayEnv(flags *flag.FlagSet) string {
+	env := os.Getenv(EnvPrefix + "ENV")
+	if flags != nil {
+		flags.Visit(func(f *flag.Flag) {
+			if f.Name == "env" {
+				env = f.Value.String()
+			}
+		})
 	}
 
-	// 6. Return pointer to populated Config struct
-	return &cfg
+	return env
 }
@@ -0,0 +1,136 @@
+package config
+
+import (
+	"errors"
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// writeFile writes content to dir/name and returns its path.
+func writeFile(t *testing.T, dir, name, content string) string {
+	t.Helper()
+
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// parsedFlags returns a FlagSet with RegisterFlags applied and args parsed.
+func parsedFlags(t *testing.T, args ...string) *flag.FlagSet {
+	t.Helper()
+
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	RegisterFlags(fs)
+	if err := fs.Parse(args); err != nil {
+		t.Fatal(err)
+	}
+	return fs
+}
+
+// TestPrecedence sets one timeout in each source and checks that defaults
+// lose to the file, the file to the environment and the environment to
+// flags.
+func TestPrecedence(t *testing.T) {
+	dir := t.TempDir()
+	path := writeFile(t, dir, "config.yaml", `
+env: local
+storage_path: `+filepath.Join(dir, "storage.db")+`
+http_server:
+  addr: localhost:9000
+  read_timeout: 1s
+  write_timeout: 1s
+  idle_timeout: 1s
+`)
+	t.Setenv("GOAPI_HTTP_SERVER_WRITE_TIMEOUT", "2s")
+	t.Setenv("GOAPI_HTTP_SERVER_IDLE_TIMEOUT", "2s")
+	fs := parsedFlags(t, "-http_server.idle_timeout=3s")
+
+	cfg, err := Load(WithPath(path), WithFlagSet(fs))
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+
+	for _, tt := range []struct {
+		name string
+		got  time.Duration
+		want time.Duration
+	}{
+		{"shutdown_timeout (default)", cfg.ShutdownTimeout, 10 * time.Second},
+		{"read_timeout (file over default)", cfg.ReadTimeout, 1 * time.Second},
+		{"write_timeout (env over file)", cfg.WriteTimeout, 2 * time.Second},
+		{"idle_timeout (flag over env)", cfg.IdleTimeout, 3 * time.Second},
+	} {
+		if tt.got != tt.want {
+			t.Errorf("%s = %v, want %v", tt.name, tt.got, tt.want)
+		}
+	}
+	if cfg.Addr != "localhost:9000" {
+		t.Errorf("addr = %q, want the file's localhost:9000", cfg.Addr)
+	}
+}
+
+// TestFieldsHaveEnvNames checks that every setting can be reached from the
+// environment under GOAPI_ followed by its upper-cased YAML path.
+func TestFieldsHaveEnvNames(t *testing.T) {
+	for _, f := range fields() {
+		want := EnvPrefix + strings.ToUpper(strings.ReplaceAll(f.Path, ".", "_"))
+		if f.Env != want {
+			t.Errorf("%s: env name = %q, want %q", f.Path, f.Env, want)
+		}
+	}
+}
+
+// unsetenv removes key from the environment for the rest of the test.
+func unsetenv(t *testing.T, key string) {
+	t.Helper()
+
+	t.Setenv(key, "") // Restores the old value on cleanup
+	os.Unsetenv(key)
+}
+
+// TestLoadWithoutFile builds a Config from GOAPI_* variables alone.
+func TestLoadWithoutFile(t *testing.T) {
+	unsetenv(t, "CONFIG_PATH")
+	t.Setenv("GOAPI_ENV", "prod")
+	t.Setenv("GOAPI_STORAGE_PATH", filepath.Join(t.TempDir(), "storage.db"))
+	t.Setenv("GOAPI_HTTP_SERVER_ADDR", "localhost:9100")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if cfg.Env != "prod" || cfg.Addr != "localhost:9100" {
+		t.Errorf("env, addr = %q, %q; want prod, localhost:9100", cfg.Env, cfg.Addr)
+	}
+	if cfg.ShutdownTimeout != 10*time.Second {
+		t.Errorf("shutdown_timeout = %v, want the 10s default", cfg.ShutdownTimeout)
+	}
+
+	if _, err := Load(RequireFile()); !errors.Is(err, ErrPathNotSet) {
+		t.Errorf("Load(RequireFile()) error = %v, want ErrPathNotSet", err)
+	}
+}
+
+// TestLoadWithoutFileMissingValue checks that a required value missing
+// with no file in play points at the ways of giving one.
+func TestLoadWithoutFileMissingValue(t *testing.T) {
+	unsetenv(t, "CONFIG_PATH")
+	unsetenv(t, "GOAPI_STORAGE_PATH")
+	t.Setenv("GOAPI_ENV", "prod")
+
+	_, err := Load()
+	if !errors.Is(err, ErrValidation) {
+		t.Fatalf("Load error = %v, want ErrValidation", err)
+	}
+	for _, want := range []string{"StoragePath", "no config file given; set CONFIG_PATH, -config or GOAPI_* variables"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("Load error %q does not mention %q", err, want)
+		}
+	}
+}
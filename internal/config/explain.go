@@ -0,0 +1,148 @@
+package config
+
+import (
+	"encoding"
+	"flag"
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// SourceKind says which kind of input a setting's value came from.
+type SourceKind string
+
+// Kinds of Source, from lowest to highest precedence.
+const (
+	SourceUnset   SourceKind = "unset"
+	SourceDefault SourceKind = "default"
+	SourceFile    SourceKind = "file"
+	SourceEnv     SourceKind = "env"
+	SourceFlag    SourceKind = "flag"
+)
+
+// Source is where a setting's effective value came from. Name is the file
+// path, environment variable or flag for the file, env and flag kinds.
+type Source struct {
+	Kind SourceKind
+	Name string
+}
+
+// String formats the source like "env GOAPI_ENV" or "default".
+func (s Source) String() string {
+	switch s.Kind {
+	case SourceFlag:
+		return "flag -" + s.Name
+	case SourceFile, SourceEnv:
+		return string(s.Kind) + " " + s.Name
+	default:
+		return string(s.Kind)
+	}
+}
+
+// Setting is one leaf of the effective Config.
+type Setting struct {
+	Path   string // Dotted YAML path, e.g. "http_server.addr"
+	Value  any    // Printable value; durations, sizes and secrets as strings, secrets redacted
+	Source Source // Where Value came from
+}
+
+// Explain loads the configuration like Load and returns every setting in
+// declaration order together with where its value came from.
+//
+// Settings that load but fail Validate, such as a storage_path whose
+// directory only exists on the production hosts, are still returned,
+// together with the validation error wrapping ErrValidation, so the
+// effective values can be inspected anywhere. Any other error yields no
+// settings.
+func Explain(opts ...Option) ([]Setting, error) {
+	o := newOptions(opts)
+	o.noValidate = true
+
+	cfg, layers, err := load(o)
+	if err != nil {
+		return nil, err
+	}
+
+	setFlags := map[string]bool{}
+	if o.flags != nil {
+		o.flags.Visit(func(f *flag.Flag) { setFlags[f.Name] = true })
+	}
+
+	var settings []Setting
+	for _, f := range fields() {
+		v := f.value(cfg)
+		settings = append(settings, Setting{
+			Path:   f.Path,
+			Value:  printable(v),
+			Source: sourceOf(f, v, layers, setFlags),
+		})
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return settings, fmt.Errorf("%w: %w", ErrValidation, err)
+	}
+
+	return settings, nil
+}
+
+// sourceOf works out which input won for f, following the precedence in
+// the package doc.
+func sourceOf(f field, v reflect.Value, layers []layer, setFlags map[string]bool) Source {
+	if setFlags[f.Path] {
+		return Source{Kind: SourceFlag, Name: f.Path}
+	}
+
+	if f.Env != "" {
+		if _, ok := os.LookupEnv(f.Env); ok {
+			return Source{Kind: SourceEnv, Name: f.Env}
+		}
+	}
+
+	for i := len(layers) - 1; i >= 0; i-- {
+		if !hasPath(layers[i].values, f.Path) {
+			continue
+		}
+		// cleanenv replaces zero values with the default, even from a file
+		if v.IsZero() && f.HasDefault {
+			break
+		}
+		return Source{Kind: SourceFile, Name: layers[i].path}
+	}
+
+	if f.HasDefault {
+		return Source{Kind: SourceDefault}
+	}
+
+	return Source{Kind: SourceUnset}
+}
+
+// hasPath reports whether the dotted path is set in m.
+func hasPath(m map[string]any, path string) bool {
+	key, rest, nested := strings.Cut(path, ".")
+
+	v, ok := m[key]
+	if !ok || !nested {
+		return ok
+	}
+
+	sub, ok := v.(map[string]any)
+	return ok && hasPath(sub, rest)
+}
+
+// printable converts v to what a config file would contain: text-encoded
+// types (sizes, secrets) and durations become strings.
+func printable(v reflect.Value) any {
+	if m, ok := v.Interface().(encoding.TextMarshaler); ok {
+		if b, err := m.MarshalText(); err == nil {
+			return string(b)
+		}
+	}
+
+	if d, ok := v.Interface().(time.Duration); ok {
+		return d.String()
+	}
+
+	return v.Interface()
+}
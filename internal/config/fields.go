@@ -0,0 +1,201 @@
+package config
+
+import (
+	"encoding"
+	"flag"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// EnvPrefix is prepended to every environment variable read by Load, on
+// top of the env and env-prefix struct tags. HttpServer.Addr, tagged
+// env:"ADDR" inside a struct with env-prefix:"HTTP_SERVER_", is therefore
+// read from GOAPI_HTTP_SERVER_ADDR.
+const EnvPrefix = "GOAPI_"
+
+// envRoot nests Config under EnvPrefix so cleanenv applies the prefix to
+// every field, including ones added later.
+type envRoot struct {
+	Config Config `env-prefix:"GOAPI_"`
+}
+
+// field describes one leaf setting of Config, as found by walking the
+// struct tags.
+type field struct {
+	Path        string       // Dotted YAML path, e.g. "http_server.addr"; also the flag name
+	Env         string       // Environment variable, e.g. "GOAPI_HTTP_SERVER_ADDR"
+	Default     string       // Value of the env-default tag
+	HasDefault  bool         // Whether an env-default tag is present
+	Required    bool         // Whether an env-required tag is present
+	Reloadable  bool         // Whether a reload:"true" tag allows changing it without a restart
+	Description string       // Value of the env-description tag
+	Enum        []string     // Allowed values from the enum tag, if any
+	Type        reflect.Type // Go type of the field
+	index       []int        // Field index path from Config, for FieldByIndex
+}
+
+// fields lists every leaf setting of Config in declaration order.
+func fields() []field {
+	return walkFields(reflect.TypeOf(Config{}), "", EnvPrefix, nil)
+}
+
+// walkFields collects the leaf fields of t. path and env are the YAML path
+// and environment prefix accumulated from the enclosing structs.
+func walkFields(t reflect.Type, path, env string, index []int) []field {
+	var out []field
+
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if !sf.IsExported() {
+			continue
+		}
+
+		name, _, _ := strings.Cut(sf.Tag.Get("yaml"), ",")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = strings.ToLower(sf.Name)
+		}
+		if path != "" {
+			name = path + "." + name
+		}
+
+		idx := append(append([]int(nil), index...), i)
+
+		// Recurse into nested sections, but not into struct-typed values
+		// such as time.Time that decode from a single string
+		if sf.Type.Kind() == reflect.Struct && !isScalar(sf.Type) {
+			out = append(out, walkFields(sf.Type, name, env+sf.Tag.Get("env-prefix"), idx)...)
+			continue
+		}
+
+		f := field{Path: name, Type: sf.Type, index: idx}
+		if e, ok := sf.Tag.Lookup("env"); ok && e != "" {
+			f.Env = env + strings.Split(e, ",")[0]
+		}
+		f.Default, f.HasDefault = sf.Tag.Lookup("env-default")
+		_, f.Required = sf.Tag.Lookup("env-required")
+		f.Reloadable = sf.Tag.Get("reload") == "true"
+		f.Description = sf.Tag.Get("env-description")
+		if enum, ok := sf.Tag.Lookup("enum"); ok {
+			f.Enum = strings.Split(enum, ",")
+		}
+
+		out = append(out, f)
+	}
+
+	return out
+}
+
+// isScalar reports whether values of t are written as a single string
+// rather than a nested section.
+func isScalar(t reflect.Type) bool {
+	return t == reflect.TypeOf(time.Time{}) || reflect.PointerTo(t).Implements(reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem())
+}
+
+// value returns the settable reflect.Value of f inside cfg.
+func (f field) value(cfg *Config) reflect.Value {
+	return reflect.ValueOf(cfg).Elem().FieldByIndex(f.index)
+}
+
+// set parses s into f's field of cfg, using the same rules as cleanenv:
+// encoding.TextUnmarshaler first, then time.Duration and the basic kinds.
+func (f field) set(cfg *Config, s string) error {
+	v := f.value(cfg)
+
+	if u, ok := v.Addr().Interface().(encoding.TextUnmarshaler); ok {
+		return u.UnmarshalText([]byte(s))
+	}
+
+	if v.Type() == reflect.TypeOf(time.Duration(0)) {
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			return err
+		}
+		v.SetInt(int64(d))
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(s)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return err
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(s, 0, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(s, 0, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		n, err := strconv.ParseFloat(s, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetFloat(n)
+	default:
+		return fmt.Errorf("unsupported type %s", v.Type())
+	}
+
+	return nil
+}
+
+// fieldFlag holds the raw command-line value of a field flag until Load
+// parses it into the Config.
+type fieldFlag struct {
+	value  string
+	isBool bool
+}
+
+func (f *fieldFlag) String() string     { return f.value }
+func (f *fieldFlag) Set(s string) error { f.value = s; return nil }
+func (f *fieldFlag) IsBoolFlag() bool   { return f.isBool }
+
+// registerFieldFlags defines one flag per field of Config, named after its
+// YAML path (e.g. -http_server.addr). Boolean fields may be given without a
+// value, like flag.Bool.
+func registerFieldFlags(fs *flag.FlagSet) {
+	for _, f := range fields() {
+		usage := "override " + f.Path
+		if f.Env != "" {
+			usage += " (env " + f.Env + ")"
+		}
+		fs.Var(&fieldFlag{isBool: f.Type.Kind() == reflect.Bool}, f.Path, usage)
+	}
+}
+
+// applyFlags copies the field flags that were set on the command line into
+// cfg.
+func applyFlags(fs *flag.FlagSet, cfg *Config) error {
+	byPath := map[string]field{}
+	for _, f := range fields() {
+		byPath[f.Path] = f
+	}
+
+	var err error
+	fs.Visit(func(fl *flag.Flag) {
+		f, ok := byPath[fl.Name]
+		if !ok || err != nil {
+			return
+		}
+		if serr := f.set(cfg, fl.Value.String()); serr != nil {
+			err = fmt.Errorf("flag -%s: %w", fl.Name, serr)
+		}
+	})
+
+	return err
+}
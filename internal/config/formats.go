@@ -0,0 +1,191 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"github.com/joho/godotenv"
+	"gopkg.in/yaml.v3"
+)
+
+// Config file formats accepted by WithFormat and -config-format.
+const (
+	FormatAuto = "auto" // Pick by file extension
+	FormatYAML = "yaml" // .yaml, .yml
+	FormatJSON = "json" // .json
+	FormatTOML = "toml" // .toml
+	FormatEnv  = "env"  // .env: GOAPI_* assignments, one per line
+)
+
+// formatsByExt maps file extensions to formats for FormatAuto.
+var formatsByExt = map[string]string{
+	".yaml": FormatYAML,
+	".yml":  FormatYAML,
+	".json": FormatJSON,
+	".toml": FormatTOML,
+	".env":  FormatEnv,
+}
+
+// formatOf returns the format to parse path with: format itself unless it
+// is empty or FormatAuto, in which case the file extension decides.
+func formatOf(path, format string) (string, error) {
+	switch format {
+	case FormatYAML, FormatJSON, FormatTOML, FormatEnv:
+		return format, nil
+	case "", FormatAuto:
+	default:
+		return "", fmt.Errorf("unknown config format %q, want one of auto, yaml, json, toml, env", format)
+	}
+
+	ext := strings.ToLower(filepath.Ext(path))
+	if f, ok := formatsByExt[ext]; ok {
+		return f, nil
+	}
+
+	return "", fmt.Errorf("cannot tell the format of %q from its extension, use -config-format", path)
+}
+
+// readFile decodes the file at path into a generic map using the given
+// format (see formatOf). An empty file yields an empty map.
+func readFile(path, format string) (map[string]any, error) {
+	format, err := formatOf(path, format)
+	if err != nil {
+		return nil, err
+	}
+
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	m := map[string]any{}
+	switch format {
+	case FormatYAML:
+		err = yaml.Unmarshal(b, &m)
+	case FormatJSON:
+		err = decodeJSON(b, &m)
+	case FormatTOML:
+		err = toml.Unmarshal(b, &m)
+	case FormatEnv:
+		m, err = decodeEnvFile(b)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if m == nil {
+		m = map[string]any{}
+	}
+
+	return m, nil
+}
+
+// decodeJSON decodes b keeping integers exact: numbers become int64 when
+// they have no fraction and float64 otherwise.
+func decodeJSON(b []byte, m *map[string]any) error {
+	dec := json.NewDecoder(strings.NewReader(string(b)))
+	dec.UseNumber()
+	if err := dec.Decode(m); err != nil {
+		return err
+	}
+	normalizeNumbers(*m)
+
+	return nil
+}
+
+// normalizeNumbers replaces json.Number values in m, recursively.
+func normalizeNumbers(m map[string]any) {
+	for k, v := range m {
+		switch v := v.(type) {
+		case json.Number:
+			if n, err := v.Int64(); err == nil {
+				m[k] = n
+			} else if f, err := v.Float64(); err == nil {
+				m[k] = f
+			}
+		case map[string]any:
+			normalizeNumbers(v)
+		}
+	}
+}
+
+// decodeEnvFile turns a dotenv file of GOAPI_* assignments into the same
+// nested map a YAML file would produce, so it layers and merges like any
+// other format. Unlike cleanenv, the values are not exported to the
+// process environment: real environment variables still override them.
+// Variables that match no setting are kept under their own name so strict
+// mode can report them.
+func decodeEnvFile(b []byte) (map[string]any, error) {
+	vars, err := godotenv.Parse(strings.NewReader(string(b)))
+	if err != nil {
+		return nil, err
+	}
+
+	byEnv := map[string]field{}
+	for _, f := range fields() {
+		if f.Env != "" {
+			byEnv[f.Env] = f
+		}
+	}
+
+	m := map[string]any{}
+	for name, raw := range vars {
+		f, ok := byEnv[name]
+		if !ok {
+			m[name] = raw
+			continue
+		}
+
+		v, err := typedValue(f, raw)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+		setPath(m, f.Path, v)
+	}
+
+	return m, nil
+}
+
+// typedValue converts the raw string of a dotenv assignment to the Go
+// value a YAML file would hold for f, so booleans and numbers decode.
+// Durations, sizes and strings stay strings.
+func typedValue(f field, raw string) (any, error) {
+	if f.Type == reflect.TypeOf(time.Duration(0)) || f.Type == reflect.TypeOf(ByteSize(0)) {
+		return raw, nil
+	}
+
+	switch f.Type.Kind() {
+	case reflect.Bool:
+		return strconv.ParseBool(raw)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.ParseInt(raw, 10, 64)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.ParseUint(raw, 10, 64)
+	case reflect.Float32, reflect.Float64:
+		return strconv.ParseFloat(raw, 64)
+	default:
+		return raw, nil
+	}
+}
+
+// setPath stores v in m under the dotted path, creating nested maps.
+func setPath(m map[string]any, path string, v any) {
+	key, rest, nested := strings.Cut(path, ".")
+	if !nested {
+		m[key] = v
+		return
+	}
+
+	sub, ok := m[key].(map[string]any)
+	if !ok {
+		sub = map[string]any{}
+		m[key] = sub
+	}
+	setPath(sub, rest, v)
+}
@@ -0,0 +1,67 @@
+package config
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+var formats = []string{"yaml", "toml", "json", "env"}
+
+// TestFormatsDecodeIdentically loads the same settings written in every
+// supported format and expects equal Configs.
+func TestFormatsDecodeIdentically(t *testing.T) {
+	want := Config{
+		Env:         "dev",
+		StoragePath: "testdata/storage.db",
+		AutoMigrate: true,
+		HttpServer: HttpServer{
+			Addr:              "localhost:9000",
+			ShutdownTimeout:   10 * time.Second,
+			ReadTimeout:       7 * time.Second,
+			ReadHeaderTimeout: 5 * time.Second,
+			WriteTimeout:      30 * time.Second,
+			IdleTimeout:       60 * time.Second,
+			MaxHeaderBytes:    1 << 20,
+			MaxBodyBytes:      1 << 20,
+			TLS:               TLS{MinVersion: "1.3"},
+		},
+		Logging: Logging{Level: "warn", Output: "stderr"},
+	}
+
+	for _, format := range formats {
+		t.Run(format, func(t *testing.T) {
+			cfg, err := Load(WithPath("testdata/cfg." + format))
+			if err != nil {
+				t.Fatalf("Load: %v", err)
+			}
+			if !reflect.DeepEqual(*cfg, want) {
+				t.Errorf("Load decoded\n%+v\nwant\n%+v", *cfg, want)
+			}
+		})
+	}
+}
+
+// TestFormatsStrict checks that every format reports a misspelt key in
+// strict mode.
+func TestFormatsStrict(t *testing.T) {
+	for _, format := range formats {
+		t.Run(format, func(t *testing.T) {
+			_, err := Load(WithPath("testdata/typo."+format), Strict())
+			if !errors.Is(err, ErrUnknownKey) || !errors.Is(err, ErrParse) {
+				t.Fatalf("Load error = %v, want ErrUnknownKey and ErrParse", err)
+			}
+		})
+	}
+}
+
+// TestWithFormat checks that an explicit format overrides the extension.
+func TestWithFormat(t *testing.T) {
+	if _, err := Load(WithPath("testdata/cfg.toml"), WithFormat(FormatYAML)); !errors.Is(err, ErrParse) {
+		t.Errorf("Load of TOML as YAML: error = %v, want ErrParse", err)
+	}
+	if _, err := Load(WithPath("testdata/cfg.toml"), WithFormat(FormatTOML)); err != nil {
+		t.Errorf("Load of TOML as TOML: %v", err)
+	}
+}
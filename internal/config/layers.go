@@ -0,0 +1,99 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// baseName is the file name (without extension) that turns on layering:
+// when Load reads base.<ext>, it deep-merges <env>.<ext> from the same
+// directory on top of it, where <env> is the resolved Config.Env.
+const baseName = "base"
+
+// layer is one config file that contributed to the result.
+type layer struct {
+	path   string
+	values map[string]any
+}
+
+// readLayers reads the file at path and, if it is a base file, the overlay
+// for env, both in the given format. env is the environment chosen by
+// flags or environment variables; when empty, the env key of the base
+// file decides.
+func readLayers(path, env, format string) ([]layer, error) {
+	base, err := readFile(path, format)
+	if err != nil {
+		return nil, fmt.Errorf("%w %s: %w", ErrParse, path, err)
+	}
+	layers := []layer{{path: path, values: base}}
+
+	ext := filepath.Ext(path)
+	if strings.TrimSuffix(filepath.Base(path), ext) != baseName {
+		return layers, nil
+	}
+
+	if env == "" {
+		env, _ = base["env"].(string)
+	}
+	if env == "" || env == baseName || strings.ContainsAny(env, `/\`) {
+		return layers, nil
+	}
+
+	// A missing overlay is fine: that environment just uses the base values
+	overlayPath := filepath.Join(filepath.Dir(path), env+ext)
+	overlay, err := readFile(overlayPath, format)
+	if errors.Is(err, fs.ErrNotExist) {
+		return layers, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w %s: %w", ErrParse, overlayPath, err)
+	}
+
+	return append(layers, layer{path: overlayPath, values: overlay}), nil
+}
+
+// merged deep-merges the layers in order, later ones winning.
+func merged(layers []layer) map[string]any {
+	out := map[string]any{}
+	for _, l := range layers {
+		mergeMaps(out, l.values)
+	}
+
+	return out
+}
+
+// mergeMaps deep-merges src into dst: nested maps are merged key by key,
+// any other value in src replaces the one in dst.
+func mergeMaps(dst, src map[string]any) {
+	for k, v := range src {
+		srcMap, srcIsMap := v.(map[string]any)
+		dstMap, dstIsMap := dst[k].(map[string]any)
+		if srcIsMap && dstIsMap {
+			mergeMaps(dstMap, srcMap)
+			continue
+		}
+		if srcIsMap {
+			// Copy so later merges never modify a layer's own map
+			cp := map[string]any{}
+			mergeMaps(cp, srcMap)
+			v = cp
+		}
+		dst[k] = v
+	}
+}
+
+// decodeMap decodes m into cfg using the yaml struct tags, so every file
+// format ends up following the same decoding rules.
+func decodeMap(m map[string]any, cfg *Config) error {
+	b, err := yaml.Marshal(m)
+	if err != nil {
+		return err
+	}
+
+	return yaml.Unmarshal(b, cfg)
+}
@@ -0,0 +1,134 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// writeLayers writes a base.yaml choosing env dev, with overlays for dev
+// and prod, and returns the path of the base file.
+func writeLayers(t *testing.T) string {
+	t.Helper()
+
+	dir := t.TempDir()
+	writeFile(t, dir, "dev.yaml", `
+http_server:
+  addr: localhost:8001
+  tls:
+    min_version: "1.3"
+`)
+	writeFile(t, dir, "prod.yaml", `
+http_server:
+  addr: localhost:8002
+logging:
+  level: error
+`)
+	return writeFile(t, dir, "base.yaml", `
+env: dev
+storage_path: `+filepath.Join(dir, "storage.db")+`
+http_server:
+  addr: localhost:8000
+  read_timeout: 7s
+  tls:
+    client_auth: none
+logging:
+  level: info
+  format: json
+`)
+}
+
+// TestOverlayMergesNestedSections checks that an overlay replaces only the
+// keys it sets, however deeply nested.
+func TestOverlayMergesNestedSections(t *testing.T) {
+	cfg, err := Load(WithPath(writeLayers(t)))
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+
+	if cfg.Env != "dev" || cfg.Addr != "localhost:8001" {
+		t.Errorf("env, addr = %q, %q; want the dev overlay's dev, localhost:8001", cfg.Env, cfg.Addr)
+	}
+	if cfg.ReadTimeout.String() != "7s" || cfg.TLS.ClientAuth != "none" {
+		t.Errorf("read_timeout, tls.client_auth = %v, %q; want the base's 7s, none", cfg.ReadTimeout, cfg.TLS.ClientAuth)
+	}
+	if cfg.TLS.MinVersion != "1.3" {
+		t.Errorf("tls.min_version = %q, want the overlay's 1.3", cfg.TLS.MinVersion)
+	}
+	if cfg.Logging.Level != "info" || cfg.Logging.Format != "json" {
+		t.Errorf("logging = %+v, want the base's info, json", cfg.Logging)
+	}
+}
+
+// TestOverlaySelection checks where the overlay's name comes from: the
+// -env flag, then GOAPI_ENV, then the env key of the base file.
+func TestOverlaySelection(t *testing.T) {
+	tests := []struct {
+		name     string
+		flags    []string
+		envVar   string
+		wantEnv  string
+		wantAddr string
+	}{
+		{"base file env", nil, "", "dev", "localhost:8001"},
+		{"GOAPI_ENV", nil, "prod", "prod", "localhost:8002"},
+		{"-env flag", []string{"-env=prod"}, "dev", "prod", "localhost:8002"},
+		{"missing overlay", []string{"-env=staging"}, "", "staging", "localhost:8000"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.envVar != "" {
+				t.Setenv("GOAPI_ENV", tt.envVar)
+			}
+
+			cfg, err := Load(WithPath(writeLayers(t)), WithFlagSet(parsedFlags(t, tt.flags...)))
+			if err != nil {
+				t.Fatalf("Load: %v", err)
+			}
+			if cfg.Env != tt.wantEnv || cfg.Addr != tt.wantAddr {
+				t.Errorf("env, addr = %q, %q; want %q, %q", cfg.Env, cfg.Addr, tt.wantEnv, tt.wantAddr)
+			}
+		})
+	}
+}
+
+// TestOverlayOnlyForBaseFiles checks that other file names are read alone.
+func TestOverlayOnlyForBaseFiles(t *testing.T) {
+	dir := filepath.Dir(writeLayers(t))
+	path := filepath.Join(dir, "app.yaml")
+	if err := os.Rename(filepath.Join(dir, "base.yaml"), path); err != nil {
+		t.Fatal(err)
+	}
+
+	layers, err := readLayers(path, "dev", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(layers) != 1 {
+		t.Errorf("read %d layers from app.yaml, want 1", len(layers))
+	}
+}
+
+// TestOverlayIgnoresPathLikeEnv checks that an env value cannot point the
+// overlay outside the base file's directory.
+func TestOverlayIgnoresPathLikeEnv(t *testing.T) {
+	path := writeLayers(t)
+	sub := filepath.Join(filepath.Dir(path), "sub")
+	if err := os.Mkdir(sub, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	base := filepath.Join(sub, "base.yaml")
+	if err := os.Rename(path, base); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, env := range []string{"../dev", "../prod", `..\dev`} {
+		layers, err := readLayers(base, env, "")
+		if err != nil {
+			t.Fatalf("env %q: %v", env, err)
+		}
+		if len(layers) != 1 {
+			t.Errorf("env %q: read %d layers, want only the base", env, len(layers))
+		}
+	}
+}
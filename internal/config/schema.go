@@ -0,0 +1,188 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+//go:generate go run ../../cmd/go-api config schema -o ../../config/schema.json
+//go:generate go run ../../cmd/go-api config docs -o ../../docs/config.md
+
+// Patterns accepted by duration and byte size settings.
+const (
+	durationPattern = `^(0|([0-9]+(\.[0-9]+)?(ns|us|µs|ms|s|m|h))+)$`
+	byteSizePattern = `^[0-9]+ *(B|KB|MB|GB|KiB|MiB|GiB)?$`
+)
+
+// JSONSchema returns a JSON Schema (draft 2020-12) describing config files,
+// generated from the struct tags of Config. Editors use it to validate
+// config/*.yaml. Required settings are not marked required in the schema
+// because overlays and environment variables may supply them.
+func JSONSchema() ([]byte, error) {
+	root := schemaObject()
+	root["$schema"] = "https://json-schema.org/draft/2020-12/schema"
+	root["title"] = "go-api configuration"
+
+	for _, f := range fields() {
+		keys := strings.Split(f.Path, ".")
+		obj := root
+		for _, key := range keys[:len(keys)-1] {
+			props := obj["properties"].(map[string]any)
+			child, ok := props[key].(map[string]any)
+			if !ok {
+				child = schemaObject()
+				props[key] = child
+			}
+			obj = child
+		}
+		obj["properties"].(map[string]any)[keys[len(keys)-1]] = fieldSchema(f)
+	}
+
+	b, err := json.MarshalIndent(root, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	return append(b, '\n'), nil
+}
+
+// Markdown returns a Markdown reference of every setting, generated from
+// the struct tags of Config.
+func Markdown() []byte {
+	var b bytes.Buffer
+
+	b.WriteString("# Configuration reference\n\n")
+	b.WriteString("<!-- Generated by `go-api config docs`; do not edit. -->\n\n")
+	b.WriteString("Settings are read, from lowest to highest precedence, from defaults, the config\n")
+	b.WriteString("file (`CONFIG_PATH` or `-config`), `" + EnvPrefix + "*` environment variables and\n")
+	b.WriteString("command-line flags named after the key (e.g. `-http_server.addr`).\n")
+	b.WriteString("Reloadable settings take effect without a restart when the config file changes\n")
+	b.WriteString("or the process receives SIGHUP.\n\n")
+	b.WriteString("| Key | Environment variable | Type | Default | Required | Reloadable | Description |\n")
+	b.WriteString("| --- | --- | --- | --- | --- | --- | --- |\n")
+
+	for _, f := range fields() {
+		def := ""
+		if f.HasDefault {
+			def = "`" + f.Default + "`"
+		}
+
+		desc := f.Description
+		if values := nonEmpty(f.Enum); len(values) > 0 {
+			desc += ". One of: `" + strings.Join(values, "`, `") + "`"
+		}
+
+		fmt.Fprintf(&b, "| `%s` | `%s` | %s | %s | %s | %s | %s |\n",
+			f.Path, f.Env, typeName(f.Type), def, yesNo(f.Required), yesNo(f.Reloadable), desc)
+	}
+
+	return b.Bytes()
+}
+
+// schemaObject returns an empty schema for a section that rejects unknown keys.
+func schemaObject() map[string]any {
+	return map[string]any{
+		"type":                 "object",
+		"additionalProperties": false,
+		"properties":           map[string]any{},
+	}
+}
+
+// fieldSchema returns the schema of a single setting.
+func fieldSchema(f field) map[string]any {
+	s := map[string]any{}
+
+	desc := f.Description
+	if f.Required {
+		desc += ". Required"
+	}
+	if f.Env != "" {
+		desc += fmt.Sprintf(" (env %s)", f.Env)
+	}
+	s["description"] = desc
+
+	switch {
+	case f.Type == reflect.TypeOf(time.Duration(0)):
+		s["type"] = "string"
+		s["pattern"] = durationPattern
+	case f.Type == reflect.TypeOf(ByteSize(0)):
+		s["type"] = []string{"integer", "string"}
+		s["minimum"] = 0
+		s["pattern"] = byteSizePattern
+	case f.Type.Kind() == reflect.Bool:
+		s["type"] = "boolean"
+	case f.Type.Kind() >= reflect.Int && f.Type.Kind() <= reflect.Uint64:
+		s["type"] = "integer"
+	default:
+		s["type"] = "string"
+	}
+
+	if len(f.Enum) > 0 {
+		s["enum"] = f.Enum
+	}
+
+	if f.HasDefault {
+		s["default"] = typedDefault(f)
+	}
+
+	return s
+}
+
+// typedDefault converts the env-default tag of f to the JSON type used in
+// the schema.
+func typedDefault(f field) any {
+	switch f.Type.Kind() {
+	case reflect.Bool:
+		if b, err := strconv.ParseBool(f.Default); err == nil {
+			return b
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if f.Type != reflect.TypeOf(time.Duration(0)) && f.Type != reflect.TypeOf(ByteSize(0)) {
+			if n, err := strconv.ParseInt(f.Default, 10, 64); err == nil {
+				return n
+			}
+		}
+	}
+
+	return f.Default
+}
+
+// typeName is the type shown in the Markdown reference.
+func typeName(t reflect.Type) string {
+	switch t {
+	case reflect.TypeOf(time.Duration(0)):
+		return "duration"
+	case reflect.TypeOf(ByteSize(0)):
+		return "byte size"
+	case reflect.TypeOf(Secret("")):
+		return "secret"
+	}
+
+	return t.Kind().String()
+}
+
+// nonEmpty drops empty strings from values.
+func nonEmpty(values []string) []string {
+	var out []string
+	for _, v := range values {
+		if v != "" {
+			out = append(out, v)
+		}
+	}
+
+	return out
+}
+
+// yesNo formats a flag for the Markdown table.
+func yesNo(b bool) string {
+	if b {
+		return "yes"
+	}
+
+	return "no"
+}
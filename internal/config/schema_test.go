@@ -0,0 +1,29 @@
+package config
+
+import (
+	"bytes"
+	"os"
+	"testing"
+)
+
+// TestGeneratedFilesUpToDate fails when config/schema.json or
+// docs/config.md no longer match the Config struct tags.
+func TestGeneratedFilesUpToDate(t *testing.T) {
+	schema, err := JSONSchema()
+	if err != nil {
+		t.Fatalf("JSONSchema: %v", err)
+	}
+
+	for path, want := range map[string][]byte{
+		"../../config/schema.json": schema,
+		"../../docs/config.md":     Markdown(),
+	} {
+		got, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatalf("read %s: %v", path, err)
+		}
+		if !bytes.Equal(got, want) {
+			t.Errorf("%s is out of date, run `go generate ./internal/config`", path)
+		}
+	}
+}
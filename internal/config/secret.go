@@ -0,0 +1,107 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+)
+
+// redacted is what a non-empty Secret prints as.
+const redacted = "[REDACTED]"
+
+// Secret is a string setting that must never end up in logs or dumps, such
+// as a password or signing key. It redacts itself in String, fmt verbs,
+// JSON, YAML and text encodings; call Value to get the actual secret.
+//
+// In config files, environment variables and flags a Secret may be given
+// as a reference that Load resolves after parsing:
+//
+//	file:/run/secrets/jwt_key   contents of the file, trailing newline removed
+//	env:DB_PASSWORD             value of the environment variable
+//
+// Any other value is used as is. References are only resolved for Secret
+// fields, so plain strings such as a "file:" SQLite URI are left alone.
+type Secret string
+
+// Value returns the secret in clear text.
+func (s Secret) Value() string {
+	return string(s)
+}
+
+// String returns "[REDACTED]", or "" for an unset secret so it is still
+// visible that nothing was configured.
+func (s Secret) String() string {
+	if s == "" {
+		return ""
+	}
+
+	return redacted
+}
+
+// GoString redacts %#v output.
+func (s Secret) GoString() string {
+	return fmt.Sprintf("config.Secret(%q)", s.String())
+}
+
+// Format redacts every fmt verb, including %q and %x.
+func (s Secret) Format(f fmt.State, verb rune) {
+	if verb == 'v' && f.Flag('#') {
+		fmt.Fprint(f, s.GoString())
+		return
+	}
+
+	fmt.Fprint(f, s.String())
+}
+
+// MarshalText redacts the YAML and text encodings.
+func (s Secret) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+// MarshalJSON redacts the JSON encoding.
+func (s Secret) MarshalJSON() ([]byte, error) {
+	return []byte(`"` + s.String() + `"`), nil
+}
+
+// resolve returns the secret a reference points to, or s itself if it is
+// not a reference.
+func (s Secret) resolve() (Secret, error) {
+	switch ref := string(s); {
+	case strings.HasPrefix(ref, "file:"):
+		b, err := os.ReadFile(strings.TrimPrefix(ref, "file:"))
+		if err != nil {
+			return "", err
+		}
+		return Secret(strings.TrimRight(string(b), "\r\n")), nil
+	case strings.HasPrefix(ref, "env:"):
+		name := strings.TrimPrefix(ref, "env:")
+		v, ok := os.LookupEnv(name)
+		if !ok {
+			return "", fmt.Errorf("environment variable %s is not set", name)
+		}
+		return Secret(v), nil
+	default:
+		return s, nil
+	}
+}
+
+// resolveSecrets replaces every Secret reference in cfg by its value.
+func resolveSecrets(cfg *Config) error {
+	secretType := reflect.TypeOf(Secret(""))
+
+	for _, f := range fields() {
+		if f.Type != secretType {
+			continue
+		}
+
+		v := f.value(cfg)
+		resolved, err := v.Interface().(Secret).resolve()
+		if err != nil {
+			return fmt.Errorf("%s: resolve secret: %w", f.Path, err)
+		}
+		v.Set(reflect.ValueOf(resolved))
+	}
+
+	return nil
+}
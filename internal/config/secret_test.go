@@ -0,0 +1,152 @@
+package config
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestSecretResolve(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "jwt_key")
+	if err := os.WriteFile(path, []byte("s3cr3t\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("GOAPI_TEST_SECRET", "from-env")
+
+	tests := []struct {
+		name    string
+		in      Secret
+		want    string
+		wantErr bool
+	}{
+		{"plain value", "plain", "plain", false},
+		{"file with trailing newline", Secret("file:" + path), "s3cr3t", false},
+		{"missing file", Secret("file:" + path + ".missing"), "", true},
+		{"env set", "env:GOAPI_TEST_SECRET", "from-env", false},
+		{"env unset", "env:GOAPI_TEST_SECRET_UNSET", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.in.resolve()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("resolve() error = %v, wantErr %t", err, tt.wantErr)
+			}
+			if got.Value() != tt.want {
+				t.Errorf("resolve() = %q, want %q", got.Value(), tt.want)
+			}
+		})
+	}
+}
+
+func TestSecretRedaction(t *testing.T) {
+	const plain = "hunter2"
+	s := Secret(plain)
+
+	type holder struct {
+		Password Secret `json:"password" yaml:"password"`
+	}
+
+	jsonOut, err := json.Marshal(holder{s})
+	if err != nil {
+		t.Fatal(err)
+	}
+	yamlOut, err := yaml.Marshal(holder{s})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var slogJSON, slogText bytes.Buffer
+	slog.New(slog.NewJSONHandler(&slogJSON, nil)).Info("login", "password", s)
+	slog.New(slog.NewTextHandler(&slogText, nil)).Info("login", "password", s)
+
+	outputs := map[string]string{
+		"%v":        fmt.Sprintf("%v", s),
+		"%s":        fmt.Sprintf("%s", s),
+		"%q":        fmt.Sprintf("%q", s),
+		"%x":        fmt.Sprintf("%x", s),
+		"%#v":       fmt.Sprintf("%#v", s),
+		"%+v":       fmt.Sprintf("%+v", holder{s}),
+		"json":      string(jsonOut),
+		"yaml":      string(yamlOut),
+		"slog json": slogJSON.String(),
+		"slog text": slogText.String(),
+	}
+	for name, out := range outputs {
+		if strings.Contains(out, plain) || strings.Contains(out, hex.EncodeToString([]byte(plain))) {
+			t.Errorf("%s leaks the secret: %s", name, out)
+		}
+		if !strings.Contains(out, redacted) {
+			t.Errorf("%s = %q, want it to contain %q", name, out, redacted)
+		}
+	}
+
+	if s.Value() != plain {
+		t.Errorf("Value() = %q, want %q", s.Value(), plain)
+	}
+	if got := Secret("").String(); got != "" {
+		t.Errorf("empty Secret String() = %q, want \"\"", got)
+	}
+}
+
+// TestLoadResolvesSecrets loads auth.api_key as a file: reference from the
+// config file and as an env: reference from GOAPI_AUTH_API_KEY, and checks
+// that Explain and encoded configs only show it redacted.
+func TestLoadResolvesSecrets(t *testing.T) {
+	dir := t.TempDir()
+	keyPath := writeFile(t, dir, "api_key", "s3cr3t\n")
+	path := writeFile(t, dir, "config.yaml", `
+env: local
+storage_path: `+filepath.Join(dir, "storage.db")+`
+auth:
+  api_key: file:`+keyPath+`
+`)
+
+	cfg, err := Load(WithPath(path))
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if got := cfg.Auth.APIKey.Value(); got != "s3cr3t" {
+		t.Errorf("auth.api_key = %q, want the file's s3cr3t", got)
+	}
+
+	out, err := yaml.Marshal(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(out), "s3cr3t") {
+		t.Errorf("encoded config leaks the secret:\n%s", out)
+	}
+	settings, err := Explain(WithPath(path))
+	if err != nil {
+		t.Fatalf("Explain: %v", err)
+	}
+	for _, s := range settings {
+		if s.Path == "auth.api_key" && fmt.Sprint(s.Value) != redacted {
+			t.Errorf("Explain shows auth.api_key as %q, want %q", s.Value, redacted)
+		}
+	}
+
+	t.Setenv("GOAPI_TEST_API_KEY", "from-env")
+	t.Setenv("GOAPI_AUTH_API_KEY", "env:GOAPI_TEST_API_KEY")
+	if cfg, err = Load(WithPath(path)); err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if got := cfg.Auth.APIKey.Value(); got != "from-env" {
+		t.Errorf("auth.api_key = %q, want from-env", got)
+	}
+
+	// A dangling reference fails the load instead of using it as the key
+	t.Setenv("GOAPI_AUTH_API_KEY", "file:"+keyPath+".missing")
+	if _, err := Load(WithPath(path)); !errors.Is(err, ErrValidation) || !strings.Contains(err.Error(), "auth.api_key") {
+		t.Errorf("Load with a missing key file: error = %v, want ErrValidation naming auth.api_key", err)
+	}
+}
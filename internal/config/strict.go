@@ -0,0 +1,191 @@
+package config
+
+import (
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+)
+
+// UnknownKeyMode says what Load does with config file keys that match no
+// setting, such as a misspelt section name.
+type UnknownKeyMode string
+
+const (
+	UnknownKeysIgnore UnknownKeyMode = "ignore" // Drop them silently
+	UnknownKeysWarn   UnknownKeyMode = "warn"   // Log each one and carry on (the default)
+	UnknownKeysFail   UnknownKeyMode = "fail"   // Fail with ErrUnknownKey
+)
+
+// parseUnknownKeyMode checks s, mapping "" to the default UnknownKeysWarn.
+func parseUnknownKeyMode(s string) (UnknownKeyMode, error) {
+	switch m := UnknownKeyMode(s); m {
+	case "":
+		return UnknownKeysWarn, nil
+	case UnknownKeysIgnore, UnknownKeysWarn, UnknownKeysFail:
+		return m, nil
+	default:
+		return "", fmt.Errorf("unknown value %q for unknown keys mode, want ignore, warn or fail", s)
+	}
+}
+
+// checkUnknownKeys applies mode to the unknown keys of every layer: it
+// returns an error wrapping ErrParse and ErrUnknownKey for the first layer
+// that has any, or removes them from the layers, logging each to log in
+// warn mode. Removing them keeps a known section holding a scalar, such as
+// "tls: 5", from failing the decode after it was reported as ignored.
+func checkUnknownKeys(layers []layer, mode UnknownKeyMode, log *slog.Logger) error {
+	for _, l := range layers {
+		keys := unknownKeys(l.values)
+		if len(keys) == 0 {
+			continue
+		}
+
+		if mode == UnknownKeysFail {
+			descs := make([]string, len(keys))
+			for i, k := range keys {
+				descs[i] = describeUnknown(k)
+			}
+			return fmt.Errorf("%w %s: %w: %s", ErrParse, l.path, ErrUnknownKey, strings.Join(descs, ", "))
+		}
+		walkUnknown(l.values, func(parent map[string]any, key, _ string) { delete(parent, key) })
+		if mode == UnknownKeysIgnore {
+			continue
+		}
+
+		_, sections := knownKeys()
+		for _, k := range keys {
+			args := []any{"file", l.path, "key", k}
+			if sections[k] {
+				args = append(args, "expected", "mapping")
+			} else if s := suggest(k); s != "" {
+				args = append(args, "did_you_mean", s)
+			}
+			log.Warn("ignoring unknown config key", args...)
+		}
+	}
+
+	return nil
+}
+
+// describeUnknown quotes key and says what is wrong with it: a known
+// section holding a scalar needs a mapping, anything else gets a
+// suggestion when a known key is close enough to be a likely typo.
+func describeUnknown(key string) string {
+	if _, sections := knownKeys(); sections[key] {
+		return fmt.Sprintf("%q (expected a mapping)", key)
+	}
+	if s := suggest(key); s != "" {
+		return fmt.Sprintf("%q (did you mean %q?)", key, s)
+	}
+
+	return fmt.Sprintf("%q", key)
+}
+
+// knownKeys returns the dotted paths of all settings and the sections that
+// contain them.
+func knownKeys() (leaves, sections map[string]bool) {
+	leaves = map[string]bool{}
+	sections = map[string]bool{}
+	for _, f := range fields() {
+		leaves[f.Path] = true
+		for p := f.Path; strings.Contains(p, "."); {
+			p = p[:strings.LastIndex(p, ".")]
+			sections[p] = true
+		}
+	}
+
+	return leaves, sections
+}
+
+// unknownKeys returns the dotted paths in m that match no Config setting,
+// sorted. A key naming a whole section (e.g. http_server) is only unknown
+// if it holds something other than a mapping.
+func unknownKeys(m map[string]any) []string {
+	var unknown []string
+	walkUnknown(m, func(_ map[string]any, _, path string) {
+		unknown = append(unknown, path)
+	})
+
+	sort.Strings(unknown)
+	return unknown
+}
+
+// walkUnknown calls fn for every key of m, at any depth, that matches no
+// Config setting, with the map holding it and its dotted path. fn may
+// delete the key from parent.
+func walkUnknown(m map[string]any, fn func(parent map[string]any, key, path string)) {
+	leaves, sections := knownKeys()
+
+	var walk func(m map[string]any, prefix string)
+	walk = func(m map[string]any, prefix string) {
+		for k, v := range m {
+			path := prefix + k
+			switch sub, isMap := v.(map[string]any); {
+			case leaves[path]:
+			case sections[path] && isMap:
+				walk(sub, path+".")
+			default:
+				fn(m, k, path)
+			}
+		}
+	}
+	walk(m, "")
+}
+
+// suggest returns the known key or environment variable name closest to
+// key by edit distance, or "" if none is within a third of its length (at
+// least 2 edits). key itself is never suggested. Environment names are
+// candidates because unknown keys of .env files are reported under their
+// variable name.
+func suggest(key string) string {
+	leaves, sections := knownKeys()
+	var candidates []string
+	for k := range leaves {
+		candidates = append(candidates, k)
+	}
+	for k := range sections {
+		candidates = append(candidates, k)
+	}
+	for _, f := range fields() {
+		if f.Env != "" {
+			candidates = append(candidates, f.Env)
+		}
+	}
+	sort.Strings(candidates) // Deterministic pick among equal distances
+
+	best, bestDist := "", max(2, len(key)/3)+1
+	for _, c := range candidates {
+		if c == key {
+			continue
+		}
+		if d := editDistance(key, c); d < bestDist {
+			best, bestDist = c, d
+		}
+	}
+
+	return best
+}
+
+// editDistance returns the Levenshtein distance between a and b.
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+
+	return prev[len(b)]
+}
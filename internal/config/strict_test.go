@@ -0,0 +1,111 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"log/slog"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestUnknownKeyMessages checks the description of each unknown key in
+// the error of fail mode.
+func TestUnknownKeyMessages(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    string
+		content string
+		want    string
+	}{
+		{"typo in section", "config.yaml", "http_sever:\n  addr: localhost:9000\n",
+			`"http_sever" (did you mean "http_server"?)`},
+		{"typo in leaf", "config.yaml", "http_server:\n  read_timout: 1s\n",
+			`"http_server.read_timout" (did you mean "http_server.read_timeout"?)`},
+		{"section holding a scalar", "config.yaml", "http_server:\n  tls: 5\n",
+			`"http_server.tls" (expected a mapping)`},
+		{"top-level section holding a scalar", "config.yaml", "logging: debug\n",
+			`"logging" (expected a mapping)`},
+		{"nothing close", "config.yaml", "completely_unrelated: 1\n",
+			`"completely_unrelated"`},
+		{"env file typo", "config.env", "GOAPI_HTTP_SERVER_ADR=localhost:9000\n",
+			`"GOAPI_HTTP_SERVER_ADR" (did you mean "GOAPI_HTTP_SERVER_ADDR"?)`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := writeFile(t, t.TempDir(), tt.file, tt.content)
+
+			_, err := Load(WithPath(path), Strict())
+			if !errors.Is(err, ErrUnknownKey) {
+				t.Fatalf("Load error = %v, want ErrUnknownKey", err)
+			}
+			msg := err.Error()
+			if !strings.HasSuffix(msg, ": "+tt.want) {
+				t.Errorf("Load error = %q, want it to end in %s", msg, tt.want)
+			}
+			if strings.Count(msg, "did you mean") > 1 || strings.Contains(msg, "expected a mapping") && strings.Contains(msg, "did you mean") {
+				t.Errorf("Load error = %q has more than one hint", msg)
+			}
+		})
+	}
+}
+
+// TestUnknownKeysWarn checks that warn mode, the default, loads the file
+// and logs each unknown key once with its hint, and that ignore mode logs
+// nothing.
+func TestUnknownKeysWarn(t *testing.T) {
+	dir := t.TempDir()
+	path := writeFile(t, dir, "config.yaml", `
+env: local
+storage_path: `+filepath.Join(dir, "storage.db")+`
+http_sever:
+  addr: localhost:9000
+logging:
+  level: debug
+  add_sorce: true
+http_server:
+  tls: 5
+`)
+
+	var logs bytes.Buffer
+	log := slog.New(slog.NewTextHandler(&logs, nil))
+	cfg, err := Load(WithPath(path), WithLogger(log))
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if cfg.Logging.Level != "debug" {
+		t.Errorf("logging.level = %q, want debug from the file", cfg.Logging.Level)
+	}
+
+	out := logs.String()
+	for _, want := range []string{
+		"key=http_sever did_you_mean=http_server",
+		"key=logging.add_sorce did_you_mean=logging.add_source",
+		"key=http_server.tls expected=mapping",
+	} {
+		if strings.Count(out, want) != 1 {
+			t.Errorf("log does not have %q once:\n%s", want, out)
+		}
+	}
+	if n := strings.Count(out, "ignoring unknown config key"); n != 3 {
+		t.Errorf("logged %d unknown keys, want 3:\n%s", n, out)
+	}
+
+	logs.Reset()
+	if _, err := Load(WithPath(path), WithLogger(log), WithUnknownKeys(UnknownKeysIgnore)); err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if logs.Len() != 0 {
+		t.Errorf("ignore mode logged:\n%s", logs.String())
+	}
+}
+
+// TestSuggestNeverEchoesKey checks that a key is not suggested as its own
+// correction.
+func TestSuggestNeverEchoesKey(t *testing.T) {
+	for _, key := range []string{"http_server", "http_server.tls", "logging", "GOAPI_ENV"} {
+		if got := suggest(key); got == key {
+			t.Errorf("suggest(%q) = %q", key, got)
+		}
+	}
+}
@@ -0,0 +1,141 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Envs lists the accepted values of Config.Env, as declared by its enum tag.
+var Envs = enumOf("env")
+
+// Validate checks the values cleanenv cannot: Env and the logging level and
+// format and the TLS version and client auth mode must be among their enum
+// values, HttpServer.Addr (and DebugAddr when set) must be a host:port
+// pair, the TLS settings must be complete (see validateTLS) and the
+// directory holding StoragePath must exist and be writable. All problems
+// are reported at once, joined with errors.Join. Load calls Validate
+// before returning.
+func (c *Config) Validate() error {
+	var errs []error
+
+	if !slices.Contains(Envs, c.Env) {
+		errs = append(errs, fmt.Errorf("env: %q is not one of %s", c.Env, strings.Join(Envs, ", ")))
+	}
+
+	// An empty value is allowed where the enum tag lists it
+	for _, e := range []struct{ path, value string }{
+		{"logging.level", c.Logging.Level},
+		{"logging.format", c.Logging.Format},
+		{"http_server.tls.min_version", c.HttpServer.TLS.MinVersion},
+		{"http_server.tls.client_auth", c.HttpServer.TLS.ClientAuth},
+	} {
+		if allowed := enumOf(e.path); !slices.Contains(allowed, e.value) {
+			errs = append(errs, fmt.Errorf("%s: %q is not one of %s", e.path, e.value, strings.Join(nonEmpty(allowed), ", ")))
+		}
+	}
+	errs = append(errs, validateTLS(c.HttpServer.TLS)...)
+
+	if err := validateAddr(c.HttpServer.Addr); err != nil {
+		errs = append(errs, fmt.Errorf("http_server.addr: %w", err))
+	}
+	if c.HttpServer.DebugAddr != "" {
+		if err := validateAddr(c.HttpServer.DebugAddr); err != nil {
+			errs = append(errs, fmt.Errorf("http_server.debug_addr: %w", err))
+		}
+	}
+
+	if err := validateStorageDir(c.StoragePath); err != nil {
+		errs = append(errs, fmt.Errorf("storage_path: %w", err))
+	}
+
+	return errors.Join(errs...)
+}
+
+// validateTLS checks what server.New would otherwise reject at startup: a
+// certificate needs its key, modes verifying client certificates need a
+// CA bundle, and every configured file must exist.
+func validateTLS(t TLS) []error {
+	var errs []error
+
+	if t.Enabled() && t.KeyFile == "" {
+		errs = append(errs, errors.New("http_server.tls.key_file: required when cert_file is set"))
+	}
+	if !t.Enabled() && t.KeyFile != "" {
+		errs = append(errs, errors.New("http_server.tls.cert_file: required when key_file is set"))
+	}
+	if (t.ClientAuth == "verify_if_given" || t.ClientAuth == "require_and_verify") && t.ClientCAFile == "" {
+		errs = append(errs, fmt.Errorf("http_server.tls.client_ca_file: required with client_auth %q", t.ClientAuth))
+	}
+
+	for _, f := range []struct{ path, file string }{
+		{"http_server.tls.cert_file", t.CertFile},
+		{"http_server.tls.key_file", t.KeyFile},
+		{"http_server.tls.client_ca_file", t.ClientCAFile},
+	} {
+		if f.file == "" {
+			continue
+		}
+		if _, err := os.Stat(f.file); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", f.path, err))
+		}
+	}
+
+	return errs
+}
+
+// enumOf returns the allowed values declared for the field at path.
+func enumOf(path string) []string {
+	for _, f := range fields() {
+		if f.Path == path {
+			return f.Enum
+		}
+	}
+
+	return nil
+}
+
+// validateAddr checks that addr is host:port with a numeric port. The host
+// may be empty to listen on every interface.
+func validateAddr(addr string) error {
+	_, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return fmt.Errorf("%q is not a host:port address", addr)
+	}
+
+	if _, err := strconv.ParseUint(port, 10, 16); err != nil {
+		return fmt.Errorf("%q has an invalid port %q", addr, port)
+	}
+
+	return nil
+}
+
+// validateStorageDir checks that the parent directory of path exists and
+// that a file can be created in it.
+func validateStorageDir(path string) error {
+	dir := filepath.Dir(path)
+
+	info, err := os.Stat(dir)
+	if err != nil {
+		return fmt.Errorf("directory %s: %w", dir, err)
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("%s is not a directory", dir)
+	}
+
+	// Permission bits do not tell the whole story (ACLs, read-only mounts),
+	// so actually try to create a file
+	f, err := os.CreateTemp(dir, ".go-api-write-check-*")
+	if err != nil {
+		return fmt.Errorf("directory %s is not writable: %w", dir, err)
+	}
+	f.Close()
+	os.Remove(f.Name())
+
+	return nil
+}
@@ -0,0 +1,55 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// validConfig returns a Config that passes Validate, storing in dir.
+func validConfig(dir string) Config {
+	return Config{
+		Env:         "local",
+		StoragePath: filepath.Join(dir, "storage.db"),
+		HttpServer:  HttpServer{Addr: "localhost:8080", TLS: TLS{MinVersion: "1.2"}},
+	}
+}
+
+func TestValidateTLS(t *testing.T) {
+	dir := t.TempDir()
+	pem := filepath.Join(dir, "x.pem")
+	if err := os.WriteFile(pem, []byte("not checked here"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		tls     TLS
+		wantErr string // Substring of the error, "" for valid
+	}{
+		{"disabled", TLS{MinVersion: "1.2"}, ""},
+		{"cert and key", TLS{MinVersion: "1.3", CertFile: pem, KeyFile: pem}, ""},
+		{"mutual TLS", TLS{MinVersion: "1.2", CertFile: pem, KeyFile: pem, ClientCAFile: pem, ClientAuth: "require_and_verify"}, ""},
+		{"unknown min_version", TLS{MinVersion: "1.7"}, `min_version: "1.7" is not one of`},
+		{"unknown client_auth", TLS{MinVersion: "1.2", ClientAuth: "always"}, `client_auth: "always" is not one of`},
+		{"cert without key", TLS{MinVersion: "1.2", CertFile: pem}, "key_file: required when cert_file is set"},
+		{"key without cert", TLS{MinVersion: "1.2", KeyFile: pem}, "cert_file: required when key_file is set"},
+		{"verify without CA", TLS{MinVersion: "1.2", CertFile: pem, KeyFile: pem, ClientAuth: "require_and_verify"}, "client_ca_file: required"},
+		{"missing cert file", TLS{MinVersion: "1.2", CertFile: "/nope", KeyFile: pem}, "cert_file: stat /nope"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := validConfig(dir)
+			cfg.HttpServer.TLS = tt.tls
+
+			err := cfg.Validate()
+			switch {
+			case tt.wantErr == "" && err != nil:
+				t.Errorf("Validate() = %v, want nil", err)
+			case tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)):
+				t.Errorf("Validate() = %v, want error containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,186 @@
+package config
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"reflect"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// Watcher keeps the current Config up to date. It reloads when one of the
+// config files changes on disk (polled by modification time and size) or
+// when the process receives SIGHUP, and publishes every successful reload
+// to its subscribers. A reload that fails to load or validate is logged
+// (see WithLogger) and the previous Config stays in place.
+//
+// Only fields tagged reload:"true" are applied. Changes to any other field,
+// such as http_server.addr, are logged as requiring a restart and otherwise
+// ignored, so Current always describes what the process is running with.
+type Watcher struct {
+	opts     options
+	interval time.Duration
+
+	mu      sync.Mutex
+	current *Config
+	stamps  map[string]fileStamp // Last seen state of each config file
+	subs    []func(*Config)
+}
+
+// fileStamp is what the Watcher compares to notice a changed file.
+type fileStamp struct {
+	modTime time.Time
+	size    int64
+}
+
+// NewWatcher loads the configuration with opts, exactly like Load, and
+// returns a Watcher that polls the files it read every interval once Run
+// is called.
+func NewWatcher(interval time.Duration, opts ...Option) (*Watcher, error) {
+	o := newOptions(opts)
+
+	cfg, layers, err := load(o)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Watcher{
+		opts:     o,
+		interval: interval,
+		current:  cfg,
+		stamps:   stampFiles(layers),
+	}, nil
+}
+
+// Current returns the Config in effect. Callers must not modify it.
+func (w *Watcher) Current() *Config {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return w.current
+}
+
+// Subscribe registers fn to be called with the new Config after every
+// reload that changed a reloadable field. fn runs on the Watcher's
+// goroutine and must not block for long.
+func (w *Watcher) Subscribe(fn func(*Config)) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.subs = append(w.subs, fn)
+}
+
+// Run watches for file changes and SIGHUP until ctx is cancelled.
+func (w *Watcher) Run(ctx context.Context) {
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	defer signal.Stop(hup)
+
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-hup:
+			w.opts.log().Info("SIGHUP received, reloading config")
+			w.Reload()
+		case <-ticker.C:
+			if w.filesChanged() {
+				w.opts.log().Info("config file changed, reloading")
+				w.Reload()
+			}
+		}
+	}
+}
+
+// Reload loads and validates the configuration again and applies the
+// reloadable fields that changed. On error the current Config is kept and
+// the files are not reloaded again until they change once more.
+func (w *Watcher) Reload() error {
+	loaded, layers, err := load(w.opts)
+
+	w.mu.Lock()
+	if err != nil {
+		// Remember the broken state of the files so the error is reported
+		// once, not on every poll, and retried on the next edit or SIGHUP
+		for path := range w.stamps {
+			w.stamps[path] = stampFile(path)
+		}
+		w.mu.Unlock()
+		w.opts.log().Error("config reload failed, keeping previous config", "err", err)
+		return err
+	}
+	w.stamps = stampFiles(layers)
+
+	// Start from the running config and copy over only what may change live
+	next := *w.current
+	var applied []string
+	for _, f := range fields() {
+		oldVal, newVal := f.value(w.current), f.value(loaded)
+		if reflect.DeepEqual(oldVal.Interface(), newVal.Interface()) {
+			continue
+		}
+		if !f.Reloadable {
+			w.opts.log().Warn("config setting changed, restart required to apply it", "setting", f.Path, "old", oldVal.Interface(), "new", newVal.Interface())
+			continue
+		}
+		f.value(&next).Set(newVal)
+		applied = append(applied, f.Path)
+	}
+
+	if len(applied) == 0 {
+		w.mu.Unlock()
+		return nil
+	}
+
+	w.current = &next
+	subs := append([]func(*Config){}, w.subs...)
+	w.mu.Unlock()
+
+	w.opts.log().Info("config reloaded", "settings", strings.Join(applied, ", "))
+	for _, fn := range subs {
+		fn(&next)
+	}
+
+	return nil
+}
+
+// filesChanged reports whether any watched file differs from its stamp.
+func (w *Watcher) filesChanged() bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	for path, stamp := range w.stamps {
+		if stampFile(path) != stamp {
+			return true
+		}
+	}
+
+	return false
+}
+
+// stampFiles records the current state of each layer's file.
+func stampFiles(layers []layer) map[string]fileStamp {
+	stamps := make(map[string]fileStamp, len(layers))
+	for _, l := range layers {
+		stamps[l.path] = stampFile(l.path)
+	}
+
+	return stamps
+}
+
+// stampFile returns the state of path, or a zero stamp if it cannot be
+// read, so a deleted file also counts as a change.
+func stampFile(path string) fileStamp {
+	info, err := os.Stat(path)
+	if err != nil {
+		return fileStamp{}
+	}
+
+	return fileStamp{modTime: info.ModTime(), size: info.Size()}
+}
@@ -0,0 +1,164 @@
+package config
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// logBuffer collects log output written from several goroutines.
+type logBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *logBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+// count returns how many log lines contain s.
+func (b *logBuffer) count(s string) int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return strings.Count(b.buf.String(), s)
+}
+
+// watcherConfig returns a config file with the given addr and log level.
+func watcherConfig(dir, addr, level string) string {
+	return `
+env: local
+storage_path: ` + filepath.Join(dir, "storage.db") + `
+http_server:
+  addr: ` + addr + `
+logging:
+  level: ` + level + `
+`
+}
+
+// newTestWatcher writes a config file and returns a Watcher on it, the
+// file's path and the Watcher's log.
+func newTestWatcher(t *testing.T, interval time.Duration) (*Watcher, string, *logBuffer) {
+	t.Helper()
+
+	dir := t.TempDir()
+	path := writeFile(t, dir, "config.yaml", watcherConfig(dir, "localhost:9000", "info"))
+	logs := &logBuffer{}
+	w, err := NewWatcher(interval, WithPath(path), WithLogger(slog.New(slog.NewTextHandler(logs, nil))))
+	if err != nil {
+		t.Fatalf("NewWatcher: %v", err)
+	}
+	return w, path, logs
+}
+
+// TestWatcherPublishesReloadable checks that a changed reloadable setting
+// becomes current and reaches subscribers.
+func TestWatcherPublishesReloadable(t *testing.T) {
+	w, path, _ := newTestWatcher(t, time.Hour)
+	var got []*Config
+	w.Subscribe(func(cfg *Config) { got = append(got, cfg) })
+
+	writeFile(t, filepath.Dir(path), "config.yaml", watcherConfig(filepath.Dir(path), "localhost:9000", "debug"))
+	if err := w.Reload(); err != nil {
+		t.Fatalf("Reload: %v", err)
+	}
+
+	if len(got) != 1 || got[0].Logging.Level != "debug" {
+		t.Fatalf("subscriber got %d configs, want 1 with level debug", len(got))
+	}
+	if w.Current() != got[0] {
+		t.Error("Current() is not the published config")
+	}
+
+	// Reloading unchanged files publishes nothing
+	if err := w.Reload(); err != nil {
+		t.Fatalf("Reload: %v", err)
+	}
+	if len(got) != 1 {
+		t.Errorf("subscriber called %d times, want 1", len(got))
+	}
+}
+
+// TestWatcherKeepsRestartSettings checks that a setting without
+// reload:"true" keeps its running value and is reported.
+func TestWatcherKeepsRestartSettings(t *testing.T) {
+	w, path, logs := newTestWatcher(t, time.Hour)
+	before := w.Current()
+	var calls int
+	w.Subscribe(func(*Config) { calls++ })
+
+	writeFile(t, filepath.Dir(path), "config.yaml", watcherConfig(filepath.Dir(path), "localhost:9001", "info"))
+	if err := w.Reload(); err != nil {
+		t.Fatalf("Reload: %v", err)
+	}
+
+	if w.Current() != before || w.Current().Addr != "localhost:9000" {
+		t.Errorf("Current() changed to addr %q, want the running config", w.Current().Addr)
+	}
+	if calls != 0 {
+		t.Errorf("subscriber called %d times, want 0", calls)
+	}
+	if logs.count("restart required") != 1 || logs.count("setting=http_server.addr") != 1 {
+		t.Errorf("addr change not reported once as needing a restart; log:\n%s", logs.buf.String())
+	}
+
+	// With a reloadable change alongside, only that one is applied
+	writeFile(t, filepath.Dir(path), "config.yaml", watcherConfig(filepath.Dir(path), "localhost:9001", "warn"))
+	if err := w.Reload(); err != nil {
+		t.Fatalf("Reload: %v", err)
+	}
+	if cur := w.Current(); cur.Addr != "localhost:9000" || cur.Logging.Level != "warn" {
+		t.Errorf("Current() addr, level = %q, %q; want localhost:9000, warn", cur.Addr, cur.Logging.Level)
+	}
+}
+
+// TestWatcherBrokenFile checks that a file that fails to load keeps the
+// running config, is reported once however often it is polled, and is
+// picked up again once fixed.
+func TestWatcherBrokenFile(t *testing.T) {
+	w, path, logs := newTestWatcher(t, 5*time.Millisecond)
+	before := w.Current()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		w.Run(ctx)
+		close(done)
+	}()
+	defer func() {
+		cancel()
+		<-done
+	}()
+
+	writeFile(t, filepath.Dir(path), "config.yaml", "http_server: [not, a, mapping\n")
+	waitFor(t, func() bool { return logs.count("config reload failed") > 0 })
+	time.Sleep(50 * time.Millisecond) // Ten more polls
+
+	if n := logs.count("config reload failed"); n != 1 {
+		t.Errorf("reload failure logged %d times, want 1", n)
+	}
+	if w.Current() != before {
+		t.Error("Current() changed after a failed reload")
+	}
+
+	writeFile(t, filepath.Dir(path), "config.yaml", watcherConfig(filepath.Dir(path), "localhost:9000", "error"))
+	waitFor(t, func() bool { return w.Current().Logging.Level == "error" })
+}
+
+// waitFor polls cond until it holds, failing the test after a second.
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+
+	for deadline := time.Now().Add(time.Second); !cond(); {
+		if time.Now().After(deadline) {
+			t.Fatal("condition not met within 1s")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
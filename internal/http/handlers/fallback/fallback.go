@@ -0,0 +1,46 @@
+// Package fallback answers requests that no route of the API matches, so
+// they fail with the same JSON envelope as every endpoint instead of
+// ServeMux's plain-text 404 and 405 pages.
+package fallback
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/SxxAq/go-api/internal/apperr"
+	"github.com/SxxAq/go-api/internal/http/response"
+)
+
+// pattern is the catch-all pattern Handler must be registered under.
+const pattern = "/"
+
+// methods are tried to tell an unknown path from a known path requested
+// with the wrong method.
+var methods = []string{
+	http.MethodGet, http.MethodHead, http.MethodPost, http.MethodPut,
+	http.MethodPatch, http.MethodDelete, http.MethodOptions,
+}
+
+// Handler must be registered on mux as the "/" pattern, which ServeMux
+// picks when nothing more specific matches. It answers 405 with an Allow
+// header when the path exists for other methods, and 404 otherwise.
+func Handler(mux *http.ServeMux) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var allowed []string
+		for _, m := range methods {
+			probe := r.Clone(r.Context())
+			probe.Method = m
+			if _, p := mux.Handler(probe); p != pattern && p != "" {
+				allowed = append(allowed, m)
+			}
+		}
+
+		if len(allowed) > 0 {
+			w.Header().Set("Allow", strings.Join(allowed, ", "))
+			response.Error(w, apperr.New(apperr.CodeMethodNotAllowed, "method %s not allowed for %s", r.Method, r.URL.Path))
+			return
+		}
+
+		response.Error(w, apperr.NotFound("no route for %s %s", r.Method, r.URL.Path))
+	}
+}
@@ -0,0 +1,47 @@
+package fallback
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/SxxAq/go-api/internal/apperr"
+	"github.com/SxxAq/go-api/internal/http/response"
+)
+
+func TestHandler(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /healthz", func(w http.ResponseWriter, r *http.Request) {})
+	mux.HandleFunc("/", Handler(mux))
+
+	tests := []struct {
+		method, path string
+		status       int
+		code         apperr.Code
+		allow        string
+	}{
+		{http.MethodGet, "/nope", http.StatusNotFound, apperr.CodeNotFound, ""},
+		{http.MethodPost, "/healthz", http.StatusMethodNotAllowed, apperr.CodeMethodNotAllowed, "GET, HEAD"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.method+" "+tt.path, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			mux.ServeHTTP(rec, httptest.NewRequest(tt.method, tt.path, nil))
+
+			if rec.Code != tt.status {
+				t.Errorf("status = %d, want %d", rec.Code, tt.status)
+			}
+			if got := rec.Header().Get("Allow"); got != tt.allow {
+				t.Errorf("Allow = %q, want %q", got, tt.allow)
+			}
+			var env response.Envelope
+			if err := json.NewDecoder(rec.Body).Decode(&env); err != nil {
+				t.Fatalf("body is not an error envelope: %v", err)
+			}
+			if env.Error.Code != tt.code {
+				t.Errorf("code = %q, want %q", env.Error.Code, tt.code)
+			}
+		})
+	}
+}
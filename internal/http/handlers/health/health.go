@@ -0,0 +1,124 @@
+// Package health implements the /healthz and /readyz probe endpoints.
+package health
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/SxxAq/go-api/internal/http/response"
+)
+
+// checkTimeout bounds how long Ready waits for all checks together, so a
+// hung database makes the probe fail rather than time out.
+const checkTimeout = 2 * time.Second
+
+// Values of the status fields in probe responses.
+const (
+	StatusOK          = "ok"
+	StatusUnavailable = "unavailable"
+)
+
+// CheckFunc reports whether a dependency is usable; nil means it is.
+type CheckFunc func(ctx context.Context) error
+
+// Checker holds the readiness checks and whether the server is shutting
+// down. It is safe for concurrent use.
+type Checker struct {
+	mu       sync.Mutex
+	names    []string // Check names in the order they were added
+	checks   map[string]CheckFunc
+	draining atomic.Bool
+}
+
+// NewChecker returns a Checker with no checks, reporting ready.
+func NewChecker() *Checker {
+	return &Checker{checks: map[string]CheckFunc{}}
+}
+
+// Add registers fn under name. Ready runs the checks in the order added.
+func (c *Checker) Add(name string, fn CheckFunc) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, ok := c.checks[name]; !ok {
+		c.names = append(c.names, name)
+	}
+	c.checks[name] = fn
+}
+
+// Drain makes Ready report 503 from now on. Call it when shutdown starts,
+// before the listener closes, so load balancers stop routing new requests
+// here while in-flight ones finish.
+func (c *Checker) Drain() {
+	c.draining.Store(true)
+}
+
+// Result is the outcome of one check.
+type Result struct {
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+// Report is the body of both probe responses.
+type Report struct {
+	Status string            `json:"status"`
+	Checks map[string]Result `json:"checks,omitempty"`
+}
+
+// Live handles GET /healthz. It only shows the process is up and serving,
+// so it never looks at dependencies: restarting the process would not fix
+// them.
+func Live() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		response.WriteJson(w, http.StatusOK, Report{Status: StatusOK})
+	}
+}
+
+// Ready handles GET /readyz. It runs every check of c plus a "shutdown"
+// check failing once Drain was called, and answers 200 when all pass or
+// 503 otherwise, with the result of each check in the body. Failures are
+// logged to log at warn level.
+func Ready(c *Checker, log *slog.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(r.Context(), checkTimeout)
+		defer cancel()
+
+		c.mu.Lock()
+		names := append([]string{}, c.names...)
+		checks := make([]CheckFunc, len(names))
+		for i, name := range names {
+			checks[i] = c.checks[name]
+		}
+		c.mu.Unlock()
+
+		report := Report{Status: StatusOK, Checks: map[string]Result{}}
+		for i, name := range names {
+			res := Result{Status: StatusOK}
+			if err := checks[i](ctx); err != nil {
+				res = Result{Status: StatusUnavailable, Error: err.Error()}
+				log.WarnContext(r.Context(), "readiness check failed", "check", name, "err", err)
+			}
+			report.Checks[name] = res
+		}
+
+		report.Checks["shutdown"] = Result{Status: StatusOK}
+		if c.draining.Load() {
+			report.Checks["shutdown"] = Result{Status: StatusUnavailable, Error: "server is shutting down"}
+		}
+
+		status := http.StatusOK
+		for _, res := range report.Checks {
+			if res.Status != StatusOK {
+				report.Status = StatusUnavailable
+				status = http.StatusServiceUnavailable
+			}
+		}
+
+		w.Header().Set("Cache-Control", "no-store")
+		response.WriteJson(w, status, report)
+	}
+}
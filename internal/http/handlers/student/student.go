@@ -0,0 +1,206 @@
+// Package student implements the /api/students HTTP handlers.
+package student
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/SxxAq/go-api/internal/apperr"
+	"github.com/SxxAq/go-api/internal/http/response"
+	"github.com/SxxAq/go-api/internal/storage"
+)
+
+// New handles POST /api/students.
+func New(store storage.Storage, log *slog.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var in studentInput
+		if !decodeBody(w, r, &in) {
+			return
+		}
+
+		student := in.student()
+		if fields := validate(student); len(fields) > 0 {
+			writeError(w, r, log, apperr.Validation(fields))
+			return
+		}
+
+		id, err := store.CreateStudent(r.Context(), student.Name, student.Email, student.Age)
+		if err != nil {
+			writeError(w, r, log, err)
+			return
+		}
+
+		student.Id = id
+		response.WriteJson(w, http.StatusCreated, student)
+	}
+}
+
+// GetById handles GET /api/students/{id}.
+func GetById(store storage.Storage, log *slog.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, ok := pathId(w, r)
+		if !ok {
+			return
+		}
+
+		student, err := store.GetStudentById(r.Context(), id)
+		if err != nil {
+			writeError(w, r, log, err)
+			return
+		}
+
+		response.WriteJson(w, http.StatusOK, student)
+	}
+}
+
+// GetList handles GET /api/students.
+func GetList(store storage.Storage, log *slog.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		students, err := store.GetStudents(r.Context())
+		if err != nil {
+			writeError(w, r, log, err)
+			return
+		}
+
+		response.WriteJson(w, http.StatusOK, students)
+	}
+}
+
+// Update handles PUT /api/students/{id}, replacing every field.
+func Update(store storage.Storage, log *slog.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, ok := pathId(w, r)
+		if !ok {
+			return
+		}
+
+		var in studentInput
+		if !decodeBody(w, r, &in) {
+			return
+		}
+		if err := in.checkId(id); err != nil {
+			writeError(w, r, log, err)
+			return
+		}
+
+		student := in.student()
+		student.Id = id
+		if fields := validate(student); len(fields) > 0 {
+			writeError(w, r, log, apperr.Validation(fields))
+			return
+		}
+
+		if err := store.UpdateStudent(r.Context(), student); err != nil {
+			writeError(w, r, log, err)
+			return
+		}
+
+		response.WriteJson(w, http.StatusOK, student)
+	}
+}
+
+// Patch handles PATCH /api/students/{id}, changing only the fields present
+// in the body.
+func Patch(store storage.Storage, log *slog.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, ok := pathId(w, r)
+		if !ok {
+			return
+		}
+
+		var in studentInput
+		if !decodeBody(w, r, &in) {
+			return
+		}
+		if err := in.checkId(id); err != nil {
+			writeError(w, r, log, err)
+			return
+		}
+
+		student, err := store.GetStudentById(r.Context(), id)
+		if err != nil {
+			writeError(w, r, log, err)
+			return
+		}
+
+		in.applyTo(&student)
+		if fields := validate(student); len(fields) > 0 {
+			writeError(w, r, log, apperr.Validation(fields))
+			return
+		}
+
+		if err := store.UpdateStudent(r.Context(), student); err != nil {
+			writeError(w, r, log, err)
+			return
+		}
+
+		response.WriteJson(w, http.StatusOK, student)
+	}
+}
+
+// Delete handles DELETE /api/students/{id}.
+func Delete(store storage.Storage, log *slog.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, ok := pathId(w, r)
+		if !ok {
+			return
+		}
+
+		if err := store.DeleteStudent(r.Context(), id); err != nil {
+			writeError(w, r, log, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// pathId parses the {id} path value, writing a 400 response if it is not
+// a positive integer.
+func pathId(w http.ResponseWriter, r *http.Request) (int64, bool) {
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil || id <= 0 {
+		response.Error(w, apperr.BadRequest("invalid id %q", r.PathValue("id")))
+		return 0, false
+	}
+
+	return id, true
+}
+
+// decodeBody decodes the JSON request body into dst, writing an error
+// response and returning false if the body is missing, too large or malformed.
+func decodeBody(w http.ResponseWriter, r *http.Request, dst any) bool {
+	dec := json.NewDecoder(r.Body)
+	dec.DisallowUnknownFields()
+
+	err := dec.Decode(dst)
+	if err == nil {
+		return true
+	}
+
+	var tooLarge *http.MaxBytesError
+	switch {
+	case errors.Is(err, io.EOF):
+		response.Error(w, apperr.BadRequest("request body is empty"))
+	case errors.As(err, &tooLarge):
+		response.Error(w, apperr.New(apperr.CodeTooLarge, "request body exceeds %d bytes", tooLarge.Limit))
+	default:
+		response.Error(w, apperr.BadRequest("invalid JSON body: %s", err.Error()))
+	}
+
+	return false
+}
+
+// writeError writes err as an error response. Errors without an apperr
+// code are logged to log, since the client only sees a generic 500.
+func writeError(w http.ResponseWriter, r *http.Request, log *slog.Logger, err error) {
+	if response.StatusCode(err) >= http.StatusInternalServerError {
+		log.ErrorContext(r.Context(), "request failed", "err", err)
+	}
+
+	response.Error(w, err)
+}
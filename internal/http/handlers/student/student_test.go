@@ -0,0 +1,252 @@
+package student
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/SxxAq/go-api/internal/apperr"
+	"github.com/SxxAq/go-api/internal/http/response"
+	"github.com/SxxAq/go-api/internal/storage"
+	"github.com/SxxAq/go-api/internal/types"
+)
+
+// memStore is an in-memory storage.Storage that reports missing ids the way
+// the real implementations do.
+type memStore struct {
+	students []types.Student
+	nextId   int64
+}
+
+var _ storage.Storage = (*memStore)(nil)
+
+func (m *memStore) CreateStudent(ctx context.Context, name, email string, age int) (int64, error) {
+	m.nextId++
+	m.students = append(m.students, types.Student{Id: m.nextId, Name: name, Email: email, Age: age})
+	return m.nextId, nil
+}
+
+func (m *memStore) GetStudentById(ctx context.Context, id int64) (types.Student, error) {
+	if i := m.index(id); i >= 0 {
+		return m.students[i], nil
+	}
+	return types.Student{}, apperr.NotFound("student %d not found", id)
+}
+
+func (m *memStore) GetStudents(ctx context.Context) ([]types.Student, error) {
+	return slices.Clone(m.students), nil
+}
+
+func (m *memStore) UpdateStudent(ctx context.Context, s types.Student) error {
+	i := m.index(s.Id)
+	if i < 0 {
+		return apperr.NotFound("student %d not found", s.Id)
+	}
+	m.students[i] = s
+	return nil
+}
+
+func (m *memStore) DeleteStudent(ctx context.Context, id int64) error {
+	i := m.index(id)
+	if i < 0 {
+		return apperr.NotFound("student %d not found", id)
+	}
+	m.students = slices.Delete(m.students, i, i+1)
+	return nil
+}
+
+func (m *memStore) Ping(ctx context.Context) error { return nil }
+func (m *memStore) Close() error                   { return nil }
+
+func (m *memStore) index(id int64) int {
+	return slices.IndexFunc(m.students, func(s types.Student) bool { return s.Id == id })
+}
+
+// newRouter registers the handlers the way serve does.
+func newRouter(store storage.Storage) *http.ServeMux {
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /api/students", New(store, log))
+	mux.HandleFunc("GET /api/students", GetList(store, log))
+	mux.HandleFunc("GET /api/students/{id}", GetById(store, log))
+	mux.HandleFunc("PUT /api/students/{id}", Update(store, log))
+	mux.HandleFunc("PATCH /api/students/{id}", Patch(store, log))
+	mux.HandleFunc("DELETE /api/students/{id}", Delete(store, log))
+	return mux
+}
+
+func do(t *testing.T, h http.Handler, method, path, body string) *httptest.ResponseRecorder {
+	t.Helper()
+
+	var r io.Reader
+	if body != "" {
+		r = strings.NewReader(body)
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(method, path, r))
+	return rec
+}
+
+func decode[T any](t *testing.T, rec *httptest.ResponseRecorder) T {
+	t.Helper()
+
+	var v T
+	if err := json.NewDecoder(rec.Body).Decode(&v); err != nil {
+		t.Fatalf("decode response body: %v", err)
+	}
+	return v
+}
+
+func TestCRUD(t *testing.T) {
+	store := &memStore{}
+	h := newRouter(store)
+
+	// Create
+	rec := do(t, h, http.MethodPost, "/api/students", `{"name":" Ada ","email":"ada@example.com","age":36}`)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("POST status = %d, want 201; body %s", rec.Code, rec.Body)
+	}
+	created := decode[types.Student](t, rec)
+	want := types.Student{Id: 1, Name: "Ada", Email: "ada@example.com", Age: 36}
+	if created != want {
+		t.Errorf("POST body = %+v, want %+v", created, want)
+	}
+
+	// Get
+	rec = do(t, h, http.MethodGet, "/api/students/1", "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("GET status = %d, want 200", rec.Code)
+	}
+	if got := decode[types.Student](t, rec); got != want {
+		t.Errorf("GET body = %+v, want %+v", got, want)
+	}
+
+	// List
+	do(t, h, http.MethodPost, "/api/students", `{"name":"Alan","email":"alan@example.com","age":41}`)
+	rec = do(t, h, http.MethodGet, "/api/students", "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("GET list status = %d, want 200", rec.Code)
+	}
+	if got := decode[[]types.Student](t, rec); len(got) != 2 || got[0] != want || got[1].Name != "Alan" {
+		t.Errorf("GET list body = %+v", got)
+	}
+
+	// Put replaces every field
+	rec = do(t, h, http.MethodPut, "/api/students/1", `{"name":"Ada L","email":"ada@example.org","age":37}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("PUT status = %d, want 200; body %s", rec.Code, rec.Body)
+	}
+	want = types.Student{Id: 1, Name: "Ada L", Email: "ada@example.org", Age: 37}
+	if got := decode[types.Student](t, rec); got != want {
+		t.Errorf("PUT body = %+v, want %+v", got, want)
+	}
+
+	// Patch changes only the fields present
+	rec = do(t, h, http.MethodPatch, "/api/students/1", `{"age":38}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("PATCH status = %d, want 200; body %s", rec.Code, rec.Body)
+	}
+	want.Age = 38
+	if got := decode[types.Student](t, rec); got != want {
+		t.Errorf("PATCH body = %+v, want %+v", got, want)
+	}
+	if got, _ := store.GetStudentById(context.Background(), 1); got != want {
+		t.Errorf("stored after PATCH = %+v, want %+v", got, want)
+	}
+
+	// Delete
+	rec = do(t, h, http.MethodDelete, "/api/students/1", "")
+	if rec.Code != http.StatusNoContent {
+		t.Fatalf("DELETE status = %d, want 204", rec.Code)
+	}
+	if rec := do(t, h, http.MethodGet, "/api/students/1", ""); rec.Code != http.StatusNotFound {
+		t.Errorf("GET after DELETE status = %d, want 404", rec.Code)
+	}
+}
+
+func TestPutRoundTrip(t *testing.T) {
+	store := &memStore{}
+	h := newRouter(store)
+	do(t, h, http.MethodPost, "/api/students", `{"name":"Ada","email":"ada@example.com","age":36}`)
+
+	// A fetched student can be sent back unchanged, id included
+	body := do(t, h, http.MethodGet, "/api/students/1", "").Body.String()
+	if rec := do(t, h, http.MethodPut, "/api/students/1", body); rec.Code != http.StatusOK {
+		t.Errorf("PUT of a fetched student: status = %d, want 200; body %s", rec.Code, rec.Body)
+	}
+
+	// An id naming another student is rejected
+	rec := do(t, h, http.MethodPut, "/api/students/1", `{"id":2,"name":"Ada","email":"ada@example.com","age":36}`)
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("PUT with another id: status = %d, want 400", rec.Code)
+	}
+	env := decode[response.Envelope](t, rec)
+	if env.Error.Code != apperr.CodeValidation || env.Error.Fields["id"] == "" {
+		t.Errorf("PUT with another id: error = %+v, want %s on field id", env.Error, apperr.CodeValidation)
+	}
+}
+
+func TestErrors(t *testing.T) {
+	store := &memStore{}
+	h := newRouter(store)
+	do(t, h, http.MethodPost, "/api/students", `{"name":"Ada","email":"ada@example.com","age":36}`)
+
+	tests := []struct {
+		name         string
+		method, path string
+		body         string
+		status       int
+		code         apperr.Code
+		fields       []string
+	}{
+		{"validation", http.MethodPost, "/api/students", `{"name":"","email":"nope","age":0}`,
+			http.StatusBadRequest, apperr.CodeValidation, []string{"age", "email", "name"}},
+		{"patch validation", http.MethodPatch, "/api/students/1", `{"age":500}`,
+			http.StatusBadRequest, apperr.CodeValidation, []string{"age"}},
+		{"unknown field", http.MethodPost, "/api/students", `{"nickname":"x"}`,
+			http.StatusBadRequest, apperr.CodeBadRequest, nil},
+		{"empty body", http.MethodPost, "/api/students", "",
+			http.StatusBadRequest, apperr.CodeBadRequest, nil},
+		{"get missing", http.MethodGet, "/api/students/99", "",
+			http.StatusNotFound, apperr.CodeNotFound, nil},
+		{"put missing", http.MethodPut, "/api/students/99", `{"name":"Ada","email":"ada@example.com","age":36}`,
+			http.StatusNotFound, apperr.CodeNotFound, nil},
+		{"delete missing", http.MethodDelete, "/api/students/99", "",
+			http.StatusNotFound, apperr.CodeNotFound, nil},
+		{"bad id", http.MethodGet, "/api/students/abc", "",
+			http.StatusBadRequest, apperr.CodeBadRequest, nil},
+		{"zero id", http.MethodDelete, "/api/students/0", "",
+			http.StatusBadRequest, apperr.CodeBadRequest, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := do(t, h, tt.method, tt.path, tt.body)
+
+			if rec.Code != tt.status {
+				t.Errorf("status = %d, want %d", rec.Code, tt.status)
+			}
+			env := decode[response.Envelope](t, rec)
+			if env.Status != response.StatusError || env.Error.Code != tt.code {
+				t.Errorf("envelope = %+v, want code %q", env, tt.code)
+			}
+			var fields []string
+			for f, msg := range env.Error.Fields {
+				if msg == "" {
+					t.Errorf("field %s has an empty message", f)
+				}
+				fields = append(fields, f)
+			}
+			slices.Sort(fields)
+			if !slices.Equal(fields, tt.fields) {
+				t.Errorf("fields = %v, want %v", fields, tt.fields)
+			}
+		})
+	}
+}
@@ -0,0 +1,87 @@
+package student
+
+import (
+	"fmt"
+	"net/mail"
+	"strings"
+
+	"github.com/SxxAq/go-api/internal/apperr"
+	"github.com/SxxAq/go-api/internal/types"
+)
+
+// Accepted range for Student.Age.
+const (
+	minAge = 1
+	maxAge = 120
+)
+
+// studentInput is the JSON body accepted by POST, PUT and PATCH. Pointer
+// fields let PATCH tell "absent" apart from a zero value. Id is accepted so
+// a student can be fetched and sent back as is: POST ignores it, PUT and
+// PATCH reject it only when it differs from the id in the path.
+type studentInput struct {
+	Id    *int64  `json:"id"`
+	Name  *string `json:"name"`
+	Email *string `json:"email"`
+	Age   *int    `json:"age"`
+}
+
+// student converts the input to a Student, leaving absent fields zero so
+// validation reports them as missing.
+func (in studentInput) student() types.Student {
+	var s types.Student
+	in.applyTo(&s)
+
+	return s
+}
+
+// applyTo overwrites the fields of s that are present in the input.
+func (in studentInput) applyTo(s *types.Student) {
+	if in.Name != nil {
+		s.Name = strings.TrimSpace(*in.Name)
+	}
+	if in.Email != nil {
+		s.Email = strings.TrimSpace(*in.Email)
+	}
+	if in.Age != nil {
+		s.Age = *in.Age
+	}
+}
+
+// checkId returns a validation error if the input names an id other than
+// pathId, and nil otherwise.
+func (in studentInput) checkId(pathId int64) error {
+	if in.Id == nil || *in.Id == pathId {
+		return nil
+	}
+
+	return apperr.Validation(map[string]string{
+		"id": fmt.Sprintf("id must match the id in the path (%d)", pathId),
+	})
+}
+
+// validate checks s and returns a message per invalid JSON field, or nil
+// if s is valid.
+func validate(s types.Student) map[string]string {
+	fields := map[string]string{}
+
+	if s.Name == "" {
+		fields["name"] = "name is required"
+	}
+
+	if s.Email == "" {
+		fields["email"] = "email is required"
+	} else if addr, err := mail.ParseAddress(s.Email); err != nil || addr.Address != s.Email {
+		fields["email"] = "email must be a valid address like name@example.com"
+	}
+
+	if s.Age < minAge || s.Age > maxAge {
+		fields["age"] = "age must be between 1 and 120"
+	}
+
+	if len(fields) == 0 {
+		return nil
+	}
+
+	return fields
+}
@@ -0,0 +1,33 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+
+	"github.com/SxxAq/go-api/internal/apperr"
+	"github.com/SxxAq/go-api/internal/http/response"
+)
+
+// RequireBearer rejects requests whose Authorization header does not carry
+// token as a Bearer credential with a 401 error response. The comparison
+// takes constant time so the token cannot be guessed byte by byte. An
+// empty token disables the check.
+func RequireBearer(token string) Middleware {
+	return func(next http.Handler) http.Handler {
+		if token == "" {
+			return next
+		}
+
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			got, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+			if !ok || subtle.ConstantTimeCompare([]byte(got), []byte(token)) != 1 {
+				w.Header().Set("WWW-Authenticate", `Bearer realm="go-api"`)
+				response.Error(w, apperr.Unauthorized("missing or invalid bearer token"))
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
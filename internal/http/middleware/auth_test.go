@@ -0,0 +1,41 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRequireBearer(t *testing.T) {
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+
+	tests := []struct {
+		name   string
+		token  string
+		header string
+		status int
+	}{
+		{"disabled", "", "", http.StatusOK},
+		{"valid", "s3cr3t", "Bearer s3cr3t", http.StatusOK},
+		{"missing", "s3cr3t", "", http.StatusUnauthorized},
+		{"wrong token", "s3cr3t", "Bearer s3cr3", http.StatusUnauthorized},
+		{"wrong scheme", "s3cr3t", "Basic s3cr3t", http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/api/students", nil)
+			if tt.header != "" {
+				r.Header.Set("Authorization", tt.header)
+			}
+			rec := httptest.NewRecorder()
+			RequireBearer(tt.token)(ok).ServeHTTP(rec, r)
+
+			if rec.Code != tt.status {
+				t.Errorf("status = %d, want %d", rec.Code, tt.status)
+			}
+			if hasChallenge := rec.Header().Get("WWW-Authenticate") != ""; hasChallenge != (tt.status == http.StatusUnauthorized) {
+				t.Errorf("WWW-Authenticate = %q with status %d", rec.Header().Get("WWW-Authenticate"), rec.Code)
+			}
+		})
+	}
+}
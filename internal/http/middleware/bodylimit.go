@@ -0,0 +1,32 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/SxxAq/go-api/internal/apperr"
+	"github.com/SxxAq/go-api/internal/http/response"
+)
+
+// MaxBodyBytes limits request bodies to limit() bytes. limit is called per
+// request so the value can change at runtime. Reads past the limit fail
+// with *http.MaxBytesError, and the server closes the connection instead
+// of draining the rest. A limit of zero or less disables the check.
+func MaxBodyBytes(limit func() int64) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			n := limit()
+			if n <= 0 {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			if r.ContentLength > n {
+				response.Error(w, apperr.New(apperr.CodeTooLarge, "request body exceeds %d bytes", n))
+				return
+			}
+
+			r.Body = http.MaxBytesReader(w, r.Body, n)
+			next.ServeHTTP(w, r)
+		})
+	}
+}
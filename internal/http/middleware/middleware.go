@@ -0,0 +1,18 @@
+// Package middleware contains http.Handler wrappers shared by every route
+// of the API.
+package middleware
+
+import "net/http"
+
+// Middleware wraps an http.Handler with extra behaviour.
+type Middleware func(http.Handler) http.Handler
+
+// Chain applies mws to h so that the first middleware is the outermost one,
+// i.e. Chain(h, a, b) handles a request as a(b(h)).
+func Chain(h http.Handler, mws ...Middleware) http.Handler {
+	for i := len(mws) - 1; i >= 0; i-- {
+		h = mws[i](h)
+	}
+
+	return h
+}
@@ -0,0 +1,55 @@
+package middleware
+
+import (
+	"errors"
+	"expvar"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"runtime/debug"
+
+	"github.com/SxxAq/go-api/internal/http/response"
+)
+
+// panics counts handler panics caught by Recover. It is published through
+// expvar as http_panics, which serve exposes on http_server.debug_addr.
+var panics = expvar.NewInt("http_panics")
+
+// Recover turns a panicking handler into a 500 response instead of letting
+// net/http log it and drop the connection. The panic value and stack are
+// logged to log with the request context, so the line carries the request
+// ID, and the http_panics counter is incremented.
+//
+// The client gets the usual JSON error body unless the handler had already
+// started the response; then the connection is aborted, since a half
+// written body cannot be turned into an error. http.ErrAbortHandler is
+// passed through untouched, as it is a deliberate abort.
+func Recover(log *slog.Logger) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			rec := &statusRecorder{ResponseWriter: w}
+			defer func() {
+				v := recover()
+				if v == nil {
+					return
+				}
+				if err, ok := v.(error); ok && errors.Is(err, http.ErrAbortHandler) {
+					panic(v)
+				}
+
+				panics.Add(1)
+				log.ErrorContext(r.Context(), "handler panicked",
+					"panic", fmt.Sprint(v),
+					"stack", string(debug.Stack()),
+				)
+
+				if rec.status != 0 {
+					panic(http.ErrAbortHandler)
+				}
+				response.Error(w, fmt.Errorf("panic: %v", v))
+			}()
+
+			next.ServeHTTP(rec, r)
+		})
+	}
+}
@@ -0,0 +1,91 @@
+package middleware
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/SxxAq/go-api/internal/apperr"
+	"github.com/SxxAq/go-api/internal/config"
+	"github.com/SxxAq/go-api/internal/http/response"
+	"github.com/SxxAq/go-api/internal/logger"
+)
+
+func TestRecover(t *testing.T) {
+	logPath := filepath.Join(t.TempDir(), "log.json")
+	l, err := logger.New(config.Logging{Level: "debug", Format: "json", Output: logPath}, "dev")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	panicking := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic("boom")
+	})
+	h := Chain(panicking, RequestID(), LogRequests(l.Logger), Recover(l.Logger))
+
+	before := panics.Value()
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/students/1", nil))
+
+	// The response is a well-formed error envelope
+	if rec.Code != http.StatusInternalServerError {
+		t.Errorf("status = %d, want 500", rec.Code)
+	}
+	if ct := rec.Header().Get("Content-Type"); ct != "application/json" {
+		t.Errorf("Content-Type = %q, want application/json", ct)
+	}
+	var env response.Envelope
+	dec := json.NewDecoder(rec.Body)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&env); err != nil {
+		t.Fatalf("body does not decode as response.Envelope: %v", err)
+	}
+	if env.Status != response.StatusError || env.Error.Code != apperr.CodeInternal {
+		t.Errorf("envelope = %+v, want status %q and code %q", env, response.StatusError, apperr.CodeInternal)
+	}
+
+	if got := panics.Value() - before; got != 1 {
+		t.Errorf("http_panics went up by %d, want 1", got)
+	}
+
+	// The panic is logged with the request ID the client got back
+	id := rec.Header().Get(RequestIDHeader)
+	if id == "" {
+		t.Fatal("no X-Request-ID in the response")
+	}
+	b, err := os.ReadFile(logPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var found bool
+	sc := bufio.NewScanner(bytes.NewReader(b))
+	sc.Buffer(nil, 1<<20) // Records carry a whole stack trace
+	for sc.Scan() {
+		var record struct {
+			Msg       string `json:"msg"`
+			RequestID string `json:"request_id"`
+			Stack     string `json:"stack"`
+		}
+		if err := json.Unmarshal(sc.Bytes(), &record); err != nil {
+			t.Fatalf("bad log line %q: %v", sc.Text(), err)
+		}
+		if record.Msg == "handler panicked" {
+			found = true
+			if record.RequestID != id {
+				t.Errorf("panic record request_id = %q, want %q", record.RequestID, id)
+			}
+			if record.Stack == "" {
+				t.Error("panic record has no stack")
+			}
+		}
+	}
+	if !found {
+		t.Errorf("no \"handler panicked\" record in log:\n%s", b)
+	}
+}
@@ -0,0 +1,71 @@
+package middleware
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"log/slog"
+	"net/http"
+
+	"github.com/SxxAq/go-api/internal/logger"
+)
+
+// RequestIDHeader carries the request ID in requests and responses.
+const RequestIDHeader = "X-Request-ID"
+
+// maxRequestIDLen bounds IDs accepted from clients so they cannot bloat
+// every log line of the request.
+const maxRequestIDLen = 128
+
+// requestIDKey is the context key of the request ID.
+type requestIDKey struct{}
+
+// RequestID gives every request an ID: the client's X-Request-ID when it
+// sent a usable one, so a trace can span services, otherwise a random one.
+// The ID is echoed in the X-Request-ID response header, available to
+// handlers through RequestIDFrom and attached as request_id to every
+// record logged with the request context (see logger.WithAttrs).
+func RequestID() Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			id := r.Header.Get(RequestIDHeader)
+			if !validRequestID(id) {
+				id = newRequestID()
+			}
+			w.Header().Set(RequestIDHeader, id)
+
+			ctx := context.WithValue(r.Context(), requestIDKey{}, id)
+			ctx = logger.WithAttrs(ctx, slog.String("request_id", id))
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// RequestIDFrom returns the ID RequestID stored in ctx, or "" if none.
+func RequestIDFrom(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// validRequestID accepts non-empty IDs of printable ASCII up to
+// maxRequestIDLen bytes, which keeps log lines and headers well-formed.
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLen {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] < 0x21 || id[i] > 0x7e {
+			return false
+		}
+	}
+
+	return true
+}
+
+// newRequestID returns 16 random bytes, hex encoded.
+func newRequestID() string {
+	b := make([]byte, 16)
+	rand.Read(b) // Never returns an error, see crypto/rand
+
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,89 @@
+// Package response writes the JSON bodies returned by the API handlers.
+// Successful responses are the resource itself; every failure uses the
+// same envelope:
+//
+//	{"status":"error","error":{"code":"not_found","message":"student 7 not found"}}
+//
+// with error.fields added for validation errors.
+package response
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/SxxAq/go-api/internal/apperr"
+)
+
+// StatusError is the value of Envelope.Status.
+const StatusError = "error"
+
+// Envelope is the body of every error response.
+type Envelope struct {
+	Status string `json:"status"`
+	Error  Body   `json:"error"`
+}
+
+// Body describes the error inside an Envelope.
+type Body struct {
+	Code    apperr.Code       `json:"code"`
+	Message string            `json:"message"`
+	Fields  map[string]string `json:"fields,omitempty"`
+}
+
+// statusCodes maps each apperr.Code to the HTTP status it is sent with.
+var statusCodes = map[apperr.Code]int{
+	apperr.CodeBadRequest:       http.StatusBadRequest,
+	apperr.CodeValidation:       http.StatusBadRequest,
+	apperr.CodeUnauthorized:     http.StatusUnauthorized,
+	apperr.CodeNotFound:         http.StatusNotFound,
+	apperr.CodeMethodNotAllowed: http.StatusMethodNotAllowed,
+	apperr.CodeConflict:         http.StatusConflict,
+	apperr.CodeTooLarge:         http.StatusRequestEntityTooLarge,
+	apperr.CodeInternal:         http.StatusInternalServerError,
+}
+
+// WriteJson writes data as a JSON body with the given status code.
+func WriteJson(w http.ResponseWriter, status int, data any) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+
+	return json.NewEncoder(w).Encode(data)
+}
+
+// Error writes err as an error Envelope with the HTTP status of its code
+// (see StatusCode). Errors that are not an *apperr.Error are reported as
+// a generic internal error so that no internals leak to clients; callers
+// should log those first.
+func Error(w http.ResponseWriter, err error) error {
+	e := asError(err)
+
+	return WriteJson(w, StatusCode(err), Envelope{
+		Status: StatusError,
+		Error: Body{
+			Code:    e.Code,
+			Message: e.Message,
+			Fields:  e.Fields,
+		},
+	})
+}
+
+// StatusCode returns the HTTP status for err: the one mapped to its code
+// when it is an *apperr.Error, 500 otherwise.
+func StatusCode(err error) int {
+	if status, ok := statusCodes[asError(err).Code]; ok {
+		return status
+	}
+
+	return http.StatusInternalServerError
+}
+
+// asError returns the *apperr.Error in err's chain, or a CodeInternal one.
+func asError(err error) *apperr.Error {
+	var e *apperr.Error
+	if errors.As(err, &e) {
+		return e
+	}
+
+	return apperr.New(apperr.CodeInternal, "internal server error")
+}
@@ -0,0 +1,89 @@
+// Package server builds the *http.Server for the API from config.HttpServer.
+package server
+
+import (
+	"log/slog"
+	"net/http"
+	"sync/atomic"
+
+	"github.com/SxxAq/go-api/internal/config"
+	"github.com/SxxAq/go-api/internal/http/middleware"
+)
+
+// Server is an *http.Server that knows whether it serves plain HTTP or
+// HTTPS, and can reload its certificates.
+type Server struct {
+	*http.Server
+	certs        *certReloader // nil when TLS is disabled
+	maxBodyBytes atomic.Int64  // Current request body limit, see SetMaxBodyBytes
+}
+
+// New returns a Server listening on cfg.Addr that serves handler with
+// cfg's timeouts, header limit and request body limit applied. Every
+// request gets an X-Request-ID and is logged to log when it completes;
+// handler panics are logged there too and answered with a 500. When
+// cfg.TLS is enabled the certificates are loaded up front, so a bad path
+// or key fails here rather than on the first handshake. Errors from the
+// http.Server itself, such as failed handshakes, are logged to log.
+func New(cfg config.HttpServer, handler http.Handler, log *slog.Logger) (*Server, error) {
+	s := &Server{}
+	s.maxBodyBytes.Store(int64(cfg.MaxBodyBytes))
+	s.Server = &http.Server{
+		Addr: cfg.Addr,
+		Handler: middleware.Chain(handler,
+			middleware.RequestID(),
+			middleware.LogRequests(log),
+			middleware.Recover(log),
+			middleware.MaxBodyBytes(s.maxBodyBytes.Load),
+		),
+		ReadTimeout:       cfg.ReadTimeout,
+		ReadHeaderTimeout: cfg.ReadHeaderTimeout,
+		WriteTimeout:      cfg.WriteTimeout,
+		IdleTimeout:       cfg.IdleTimeout,
+		MaxHeaderBytes:    int(cfg.MaxHeaderBytes),
+		ErrorLog:          slog.NewLogLogger(log.Handler(), slog.LevelError),
+	}
+
+	if cfg.TLS.Enabled() {
+		certs, err := newCertReloader(cfg.TLS)
+		if err != nil {
+			return nil, err
+		}
+		s.certs = certs
+		s.TLSConfig = certs.TLSConfig()
+	}
+
+	return s, nil
+}
+
+// TLS reports whether the server serves HTTPS.
+func (s *Server) TLS() bool {
+	return s.certs != nil
+}
+
+// ListenAndServe serves HTTPS when TLS is configured and plain HTTP
+// otherwise. Like http.Server it always returns a non-nil error.
+func (s *Server) ListenAndServe() error {
+	if s.certs != nil {
+		// Certificates come from TLSConfig, so no file names are passed here
+		return s.Server.ListenAndServeTLS("", "")
+	}
+
+	return s.Server.ListenAndServe()
+}
+
+// SetMaxBodyBytes changes the request body limit for requests that start
+// after the call.
+func (s *Server) SetMaxBodyBytes(n int64) {
+	s.maxBodyBytes.Store(n)
+}
+
+// ReloadCertificates re-reads the TLS certificate, key and client CA
+// bundle from disk. It is a no-op when TLS is disabled.
+func (s *Server) ReloadCertificates() error {
+	if s.certs == nil {
+		return nil
+	}
+
+	return s.certs.Reload()
+}
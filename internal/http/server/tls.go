@@ -0,0 +1,120 @@
+package server
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+	"sync/atomic"
+
+	"github.com/SxxAq/go-api/internal/config"
+)
+
+// tlsVersions maps config.TLS.MinVersion values to crypto/tls constants.
+var tlsVersions = map[string]uint16{
+	"1.0": tls.VersionTLS10,
+	"1.1": tls.VersionTLS11,
+	"1.2": tls.VersionTLS12,
+	"1.3": tls.VersionTLS13,
+}
+
+// clientAuthModes maps config.TLS.ClientAuth values to crypto/tls constants.
+var clientAuthModes = map[string]tls.ClientAuthType{
+	"none":               tls.NoClientCert,
+	"request":            tls.RequestClientCert,
+	"require":            tls.RequireAnyClientCert,
+	"verify_if_given":    tls.VerifyClientCertIfGiven,
+	"require_and_verify": tls.RequireAndVerifyClientCert,
+}
+
+// certReloader serves the certificate and client CA pool most recently
+// loaded from disk. Reload swaps them atomically, so certificates rotated
+// by cert-manager take effect for new handshakes without a restart.
+type certReloader struct {
+	cfg     config.TLS
+	base    *tls.Config                // Static settings shared by every handshake
+	current atomic.Pointer[tls.Config] // base plus the latest certificate and CA pool
+}
+
+// newCertReloader validates cfg and performs the initial load.
+func newCertReloader(cfg config.TLS) (*certReloader, error) {
+	if cfg.KeyFile == "" {
+		return nil, errors.New("tls: key_file is required when cert_file is set")
+	}
+
+	minVersion, ok := tlsVersions[cfg.MinVersion]
+	if !ok {
+		return nil, fmt.Errorf("tls: unsupported min_version %q", cfg.MinVersion)
+	}
+
+	// Default to full verification when a client CA bundle is configured
+	mode := cfg.ClientAuth
+	if mode == "" {
+		mode = "none"
+		if cfg.ClientCAFile != "" {
+			mode = "require_and_verify"
+		}
+	}
+	clientAuth, ok := clientAuthModes[mode]
+	if !ok {
+		return nil, fmt.Errorf("tls: unsupported client_auth %q", cfg.ClientAuth)
+	}
+	if clientAuth >= tls.VerifyClientCertIfGiven && cfg.ClientCAFile == "" {
+		return nil, fmt.Errorf("tls: client_auth %q requires client_ca_file", mode)
+	}
+
+	r := &certReloader{
+		cfg: cfg,
+		base: &tls.Config{
+			MinVersion: minVersion,
+			ClientAuth: clientAuth,
+			NextProtos: []string{"h2", "http/1.1"},
+		},
+	}
+	if err := r.Reload(); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// Reload reads the certificate, key and client CA bundle from disk. On
+// error the previously loaded material stays in use.
+func (r *certReloader) Reload() error {
+	cert, err := tls.LoadX509KeyPair(r.cfg.CertFile, r.cfg.KeyFile)
+	if err != nil {
+		return fmt.Errorf("tls: load key pair: %w", err)
+	}
+
+	next := r.base.Clone()
+	next.Certificates = []tls.Certificate{cert}
+
+	if r.cfg.ClientCAFile != "" {
+		pem, err := os.ReadFile(r.cfg.ClientCAFile)
+		if err != nil {
+			return fmt.Errorf("tls: read client CA bundle: %w", err)
+		}
+
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return fmt.Errorf("tls: no certificates found in %s", r.cfg.ClientCAFile)
+		}
+		next.ClientCAs = pool
+	}
+
+	r.current.Store(next)
+	return nil
+}
+
+// TLSConfig returns the config to install on http.Server. It resolves the
+// per-handshake settings through GetConfigForClient so reloads are picked
+// up immediately.
+func (r *certReloader) TLSConfig() *tls.Config {
+	cfg := r.base.Clone()
+	cfg.GetConfigForClient = func(*tls.ClientHelloInfo) (*tls.Config, error) {
+		return r.current.Load(), nil
+	}
+
+	return cfg
+}
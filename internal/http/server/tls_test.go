@@ -0,0 +1,206 @@
+package server
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io"
+	"log/slog"
+	"math/big"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/SxxAq/go-api/internal/config"
+)
+
+// testCA is a throwaway certificate authority for one test.
+type testCA struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+	pem  []byte
+}
+
+func newTestCA(t *testing.T) *testCA {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "go-api test CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign,
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return &testCA{cert: cert, key: key, pem: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})}
+}
+
+// issue signs a leaf certificate for 127.0.0.1 with the given serial and
+// usage, returning the certificate and key PEM.
+func (ca *testCA) issue(t *testing.T, serial int64, usage x509.ExtKeyUsage) (certPEM, keyPEM []byte) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(serial),
+		Subject:      pkix.Name{CommonName: "127.0.0.1"},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{usage},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, ca.cert, &key.PublicKey, ca.key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+}
+
+func writeFile(t *testing.T, path string, data []byte) {
+	t.Helper()
+
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// startTLS serves a Server built from tlsCfg on a random local port and
+// returns its base URL.
+func startTLS(t *testing.T, tlsCfg config.TLS) (*Server, string) {
+	t.Helper()
+
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, "ok")
+	})
+	srv, err := New(config.HttpServer{TLS: tlsCfg}, handler, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	go srv.Server.ServeTLS(ln, "", "")
+	t.Cleanup(func() { srv.Close() })
+
+	return srv, "https://" + ln.Addr().String()
+}
+
+// client returns an HTTPS client trusting ca and presenting cert, if any.
+func client(ca *testCA, cert *tls.Certificate) *http.Client {
+	pool := x509.NewCertPool()
+	pool.AppendCertsFromPEM(ca.pem)
+
+	cfg := &tls.Config{RootCAs: pool}
+	if cert != nil {
+		cfg.Certificates = []tls.Certificate{*cert}
+	}
+
+	return &http.Client{Transport: &http.Transport{TLSClientConfig: cfg, DisableKeepAlives: true}}
+}
+
+func TestTLS(t *testing.T) {
+	dir := t.TempDir()
+	ca := newTestCA(t)
+
+	certFile := filepath.Join(dir, "server.crt")
+	keyFile := filepath.Join(dir, "server.key")
+	caFile := filepath.Join(dir, "ca.crt")
+	serverCert, serverKey := ca.issue(t, 10, x509.ExtKeyUsageServerAuth)
+	writeFile(t, certFile, serverCert)
+	writeFile(t, keyFile, serverKey)
+	writeFile(t, caFile, ca.pem)
+
+	clientCertPEM, clientKeyPEM := ca.issue(t, 20, x509.ExtKeyUsageClientAuth)
+	clientCert, err := tls.X509KeyPair(clientCertPEM, clientKeyPEM)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	srv, url := startTLS(t, config.TLS{
+		CertFile:     certFile,
+		KeyFile:      keyFile,
+		ClientCAFile: caFile,
+		MinVersion:   "1.2",
+		ClientAuth:   "require_and_verify",
+	})
+	if !srv.TLS() {
+		t.Fatal("TLS() = false with cert_file set")
+	}
+
+	// leafSerial makes a request with a client certificate and returns the
+	// serial number of the certificate the server presented
+	leafSerial := func() int64 {
+		t.Helper()
+
+		res, err := client(ca, &clientCert).Get(url)
+		if err != nil {
+			t.Fatalf("GET with client certificate: %v", err)
+		}
+		defer res.Body.Close()
+
+		if body, _ := io.ReadAll(res.Body); res.StatusCode != http.StatusOK || string(body) != "ok" {
+			t.Fatalf("got %d %q, want 200 \"ok\"", res.StatusCode, body)
+		}
+		return res.TLS.PeerCertificates[0].SerialNumber.Int64()
+	}
+
+	t.Run("handshake", func(t *testing.T) {
+		if got := leafSerial(); got != 10 {
+			t.Errorf("server certificate serial = %d, want 10", got)
+		}
+	})
+
+	t.Run("require_and_verify rejects client without certificate", func(t *testing.T) {
+		res, err := client(ca, nil).Get(url)
+		if err == nil {
+			res.Body.Close()
+			t.Fatalf("GET without client certificate succeeded with %d, want handshake failure", res.StatusCode)
+		}
+	})
+
+	t.Run("reload serves new leaf", func(t *testing.T) {
+		newCert, newKey := ca.issue(t, 11, x509.ExtKeyUsageServerAuth)
+		writeFile(t, certFile, newCert)
+		writeFile(t, keyFile, newKey)
+
+		if err := srv.ReloadCertificates(); err != nil {
+			t.Fatalf("ReloadCertificates: %v", err)
+		}
+		if got := leafSerial(); got != 11 {
+			t.Errorf("server certificate serial after reload = %d, want 11", got)
+		}
+	})
+}
@@ -0,0 +1,44 @@
+package logger
+
+import (
+	"context"
+	"log/slog"
+)
+
+// attrsKey is the context key under which WithAttrs stores attributes.
+type attrsKey struct{}
+
+// WithAttrs returns a copy of ctx carrying attrs. Every record logged with
+// that context through a Logger, e.g. via InfoContext, gets them added, so
+// a request ID set once by middleware shows up in every line the request
+// produces.
+func WithAttrs(ctx context.Context, attrs ...slog.Attr) context.Context {
+	prev, _ := ctx.Value(attrsKey{}).([]slog.Attr)
+	all := make([]slog.Attr, 0, len(prev)+len(attrs))
+	all = append(append(all, prev...), attrs...)
+
+	return context.WithValue(ctx, attrsKey{}, all)
+}
+
+// contextHandler is a slog.Handler adding the attributes stored by
+// WithAttrs to each record before passing it on.
+type contextHandler struct {
+	slog.Handler
+}
+
+func (h contextHandler) Handle(ctx context.Context, r slog.Record) error {
+	if attrs, ok := ctx.Value(attrsKey{}).([]slog.Attr); ok {
+		r = r.Clone()
+		r.AddAttrs(attrs...)
+	}
+
+	return h.Handler.Handle(ctx, r)
+}
+
+func (h contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return contextHandler{h.Handler.WithAttrs(attrs)}
+}
+
+func (h contextHandler) WithGroup(name string) slog.Handler {
+	return contextHandler{h.Handler.WithGroup(name)}
+}
@@ -0,0 +1,100 @@
+// Package logger builds the application's *slog.Logger from
+// config.Logging. Packages that log take a *slog.Logger from their caller
+// instead of writing to the standard log package.
+package logger
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+
+	"github.com/SxxAq/go-api/internal/config"
+)
+
+// Logger is a *slog.Logger whose level can be changed while it is in use,
+// so logging.level can be hot-reloaded.
+type Logger struct {
+	*slog.Logger
+	level *slog.LevelVar
+	env   string    // Picks the level when logging.level is empty
+	out   io.Closer // Log file opened for logging.output, nil for stdout/stderr
+}
+
+// New returns a Logger writing to cfg.Output in cfg.Format at cfg.Level.
+// An empty Format or Level is chosen from env: text at debug level in
+// local, JSON at info level elsewhere. Records logged with a context also
+// get the attributes stored in it by WithAttrs. Close the Logger to close
+// its log file, if any.
+func New(cfg config.Logging, env string) (*Logger, error) {
+	l := &Logger{level: new(slog.LevelVar), env: env}
+	if err := l.SetLevel(cfg.Level); err != nil {
+		return nil, err
+	}
+
+	var w io.Writer
+	switch cfg.Output {
+	case "", "stderr":
+		w = os.Stderr
+	case "stdout":
+		w = os.Stdout
+	default:
+		f, err := os.OpenFile(cfg.Output, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o644)
+		if err != nil {
+			return nil, fmt.Errorf("open log output: %w", err)
+		}
+		w, l.out = f, f
+	}
+
+	format := cfg.Format
+	if format == "" {
+		format = "json"
+		if env == "local" {
+			format = "text"
+		}
+	}
+
+	opts := &slog.HandlerOptions{Level: l.level, AddSource: cfg.AddSource}
+	var h slog.Handler
+	switch format {
+	case "text":
+		h = slog.NewTextHandler(w, opts)
+	case "json":
+		h = slog.NewJSONHandler(w, opts)
+	default:
+		l.Close()
+		return nil, fmt.Errorf("unknown log format %q, want text or json", format)
+	}
+	l.Logger = slog.New(contextHandler{h})
+
+	return l, nil
+}
+
+// SetLevel changes the lowest level logged to level (debug, info, warn or
+// error), or to the default for the Logger's environment when level is
+// empty. It is safe to call while the Logger is in use.
+func (l *Logger) SetLevel(level string) error {
+	if level == "" {
+		level = "info"
+		if l.env == "local" {
+			level = "debug"
+		}
+	}
+
+	var lvl slog.Level
+	if err := lvl.UnmarshalText([]byte(level)); err != nil {
+		return fmt.Errorf("invalid log level %q: %w", level, err)
+	}
+	l.level.Set(lvl)
+
+	return nil
+}
+
+// Close closes the log file, if logging goes to one.
+func (l *Logger) Close() error {
+	if l.out == nil {
+		return nil
+	}
+
+	return l.out.Close()
+}
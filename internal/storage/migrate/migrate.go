@@ -0,0 +1,300 @@
+// Package migrate applies ordered, versioned SQL migrations to a database
+// and records them in a schema_migrations table.
+//
+// Migrations are read from an fs.FS (usually an embed.FS) holding pairs of
+// files named <version>_<name>.up.sql and <version>_<name>.down.sql, e.g.
+// 0001_create_students.up.sql. Every run of To happens inside a single
+// transaction: if any statement fails, nothing is applied and the recorded
+// version stays where it was.
+package migrate
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// Errors returned by the Migrator.
+var (
+	// ErrUnknownVersion means the requested target version has no migration.
+	ErrUnknownVersion = errors.New("unknown migration version")
+	// ErrIrreversible means a migration that must be rolled back has no down file.
+	ErrIrreversible = errors.New("migration has no down script")
+)
+
+// fileName matches migration files such as 0001_create_students.up.sql.
+var fileName = regexp.MustCompile(`^(\d+)_(\w+)\.(up|down)\.sql$`)
+
+// Migration is a single schema change.
+type Migration struct {
+	Version int64
+	Name    string
+	Up      string // SQL applied when migrating up to Version
+	Down    string // SQL that reverts Up; empty if irreversible
+}
+
+// Status describes whether a migration has been applied.
+type Status struct {
+	Migration
+	Applied   bool
+	AppliedAt time.Time
+}
+
+// Migrator applies a fixed set of migrations to db.
+type Migrator struct {
+	db         *sql.DB
+	migrations []Migration // Sorted by ascending version
+}
+
+// New reads the migrations in the root of fsys. It fails if a file name
+// does not follow the naming scheme or a version is used twice.
+func New(db *sql.DB, fsys fs.FS) (*Migrator, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, fmt.Errorf("read migrations: %w", err)
+	}
+
+	byVersion := map[int64]*Migration{}
+	for _, e := range entries {
+		if e.IsDir() || path.Ext(e.Name()) != ".sql" {
+			continue
+		}
+
+		m := fileName.FindStringSubmatch(e.Name())
+		if m == nil {
+			return nil, fmt.Errorf("migration %s: name must look like 0001_name.up.sql", e.Name())
+		}
+
+		version, err := strconv.ParseInt(m[1], 10, 64)
+		if err != nil || version <= 0 {
+			return nil, fmt.Errorf("migration %s: version must be a positive integer", e.Name())
+		}
+
+		body, err := fs.ReadFile(fsys, e.Name())
+		if err != nil {
+			return nil, fmt.Errorf("read migration %s: %w", e.Name(), err)
+		}
+
+		mig, ok := byVersion[version]
+		if !ok {
+			mig = &Migration{Version: version, Name: m[2]}
+			byVersion[version] = mig
+		}
+		if mig.Name != m[2] {
+			return nil, fmt.Errorf("migration %s: version %d already used by %q", e.Name(), version, mig.Name)
+		}
+
+		if m[3] == "up" {
+			mig.Up = string(body)
+		} else {
+			mig.Down = string(body)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, mig := range byVersion {
+		if mig.Up == "" {
+			return nil, fmt.Errorf("migration %d_%s: missing up script", mig.Version, mig.Name)
+		}
+		migrations = append(migrations, *mig)
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+
+	return &Migrator{db: db, migrations: migrations}, nil
+}
+
+// Latest returns the highest known migration version, or 0 if there are none.
+func (m *Migrator) Latest() int64 {
+	if len(m.migrations) == 0 {
+		return 0
+	}
+
+	return m.migrations[len(m.migrations)-1].Version
+}
+
+// Version returns the currently applied version, 0 meaning none.
+func (m *Migrator) Version(ctx context.Context) (int64, error) {
+	if err := m.ensureTable(ctx); err != nil {
+		return 0, err
+	}
+
+	return currentVersion(ctx, m.db)
+}
+
+// Up applies every pending migration.
+func (m *Migrator) Up(ctx context.Context) ([]Migration, error) {
+	return m.To(ctx, m.Latest())
+}
+
+// Down reverts the most recently applied migration. It is a no-op at
+// version 0.
+func (m *Migrator) Down(ctx context.Context) ([]Migration, error) {
+	cur, err := m.Version(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	target := int64(0)
+	for _, mig := range m.migrations {
+		if mig.Version < cur {
+			target = mig.Version
+		}
+	}
+
+	return m.To(ctx, target)
+}
+
+// To migrates up or down until target is the applied version and returns
+// the migrations that were run, in the order they ran. target must be 0 or
+// a known version.
+func (m *Migrator) To(ctx context.Context, target int64) ([]Migration, error) {
+	if target != 0 && !m.known(target) {
+		return nil, fmt.Errorf("%w: %d", ErrUnknownVersion, target)
+	}
+
+	if err := m.ensureTable(ctx); err != nil {
+		return nil, err
+	}
+
+	// 1. One transaction for the whole run, so a failure changes nothing
+	tx, err := m.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("begin migration: %w", err)
+	}
+	defer tx.Rollback() // No-op after a successful Commit
+
+	cur, err := currentVersion(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. Run up scripts in ascending order, or down scripts in descending order
+	var ran []Migration
+	if target >= cur {
+		for _, mig := range m.migrations {
+			if mig.Version <= cur || mig.Version > target {
+				continue
+			}
+			if err := apply(ctx, tx, mig.Up, "INSERT INTO schema_migrations (version, name) VALUES (?, ?)", mig.Version, mig.Name); err != nil {
+				return nil, fmt.Errorf("migrate up %d_%s: %w", mig.Version, mig.Name, err)
+			}
+			ran = append(ran, mig)
+		}
+	} else {
+		for i := len(m.migrations) - 1; i >= 0; i-- {
+			mig := m.migrations[i]
+			if mig.Version > cur || mig.Version <= target {
+				continue
+			}
+			if mig.Down == "" {
+				return nil, fmt.Errorf("migrate down %d_%s: %w", mig.Version, mig.Name, ErrIrreversible)
+			}
+			if err := apply(ctx, tx, mig.Down, "DELETE FROM schema_migrations WHERE version = ?", mig.Version); err != nil {
+				return nil, fmt.Errorf("migrate down %d_%s: %w", mig.Version, mig.Name, err)
+			}
+			ran = append(ran, mig)
+		}
+	}
+
+	// 3. Make it all visible at once
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("commit migration: %w", err)
+	}
+
+	return ran, nil
+}
+
+// Status lists every known migration and whether it has been applied.
+func (m *Migrator) Status(ctx context.Context) ([]Status, error) {
+	if err := m.ensureTable(ctx); err != nil {
+		return nil, err
+	}
+
+	rows, err := m.db.QueryContext(ctx, "SELECT version, applied_at FROM schema_migrations")
+	if err != nil {
+		return nil, fmt.Errorf("query schema_migrations: %w", err)
+	}
+	defer rows.Close()
+
+	applied := map[int64]time.Time{}
+	for rows.Next() {
+		var (
+			version int64
+			at      time.Time
+		)
+		if err := rows.Scan(&version, &at); err != nil {
+			return nil, fmt.Errorf("scan schema_migrations: %w", err)
+		}
+		applied[version] = at
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("query schema_migrations: %w", err)
+	}
+
+	statuses := make([]Status, 0, len(m.migrations))
+	for _, mig := range m.migrations {
+		at, ok := applied[mig.Version]
+		statuses = append(statuses, Status{Migration: mig, Applied: ok, AppliedAt: at})
+	}
+
+	return statuses, nil
+}
+
+// known reports whether version belongs to a migration.
+func (m *Migrator) known(version int64) bool {
+	for _, mig := range m.migrations {
+		if mig.Version == version {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ensureTable creates the schema_migrations table if needed.
+func (m *Migrator) ensureTable(ctx context.Context) error {
+	_, err := m.db.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS schema_migrations (
+		version INTEGER PRIMARY KEY,
+		name TEXT NOT NULL,
+		applied_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+	)`)
+	if err != nil {
+		return fmt.Errorf("create schema_migrations: %w", err)
+	}
+
+	return nil
+}
+
+// queryer is the subset of *sql.DB and *sql.Tx used by currentVersion.
+type queryer interface {
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+// currentVersion returns the highest applied version.
+func currentVersion(ctx context.Context, q queryer) (int64, error) {
+	var version int64
+	if err := q.QueryRowContext(ctx, "SELECT COALESCE(MAX(version), 0) FROM schema_migrations").Scan(&version); err != nil {
+		return 0, fmt.Errorf("read schema version: %w", err)
+	}
+
+	return version, nil
+}
+
+// apply runs script and then the bookkeeping statement inside tx.
+func apply(ctx context.Context, tx *sql.Tx, script, record string, args ...any) error {
+	if _, err := tx.ExecContext(ctx, script); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, record, args...); err != nil {
+		return fmt.Errorf("record version: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,208 @@
+package migrate
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"path/filepath"
+	"slices"
+	"testing"
+	"testing/fstest"
+
+	_ "github.com/mattn/go-sqlite3" // Registers the "sqlite3" database/sql driver
+)
+
+// testFS holds three migrations; 0003 has no down script.
+var testFS = fstest.MapFS{
+	"0001_create_a.up.sql":   {Data: []byte("CREATE TABLE a (id INTEGER PRIMARY KEY);")},
+	"0001_create_a.down.sql": {Data: []byte("DROP TABLE a;")},
+	"0002_create_b.up.sql":   {Data: []byte("CREATE TABLE b (id INTEGER PRIMARY KEY);")},
+	"0002_create_b.down.sql": {Data: []byte("DROP TABLE b;")},
+	"0003_create_c.up.sql":   {Data: []byte("CREATE TABLE c (id INTEGER PRIMARY KEY);")},
+}
+
+func openDB(t *testing.T) *sql.DB {
+	t.Helper()
+
+	db, err := sql.Open("sqlite3", filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+func newMigrator(t *testing.T, db *sql.DB, fsys fstest.MapFS) *Migrator {
+	t.Helper()
+
+	m, err := New(db, fsys)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return m
+}
+
+// versions returns the versions of ran, in order.
+func versions(ran []Migration) []int64 {
+	var vs []int64
+	for _, mig := range ran {
+		vs = append(vs, mig.Version)
+	}
+	return vs
+}
+
+// checkState fails the test unless the applied version is want and exactly
+// the given tables exist.
+func checkState(t *testing.T, db *sql.DB, m *Migrator, want int64, tables ...string) {
+	t.Helper()
+
+	ctx := context.Background()
+	if got, err := m.Version(ctx); err != nil || got != want {
+		t.Errorf("Version() = %d, %v; want %d", got, err, want)
+	}
+	for _, name := range []string{"a", "b", "c"} {
+		var n int
+		if err := db.QueryRowContext(ctx, "SELECT count(*) FROM sqlite_master WHERE type = 'table' AND name = ?", name).Scan(&n); err != nil {
+			t.Fatal(err)
+		}
+		if exists, want := n == 1, slices.Contains(tables, name); exists != want {
+			t.Errorf("table %s exists = %v, want %v", name, exists, want)
+		}
+	}
+}
+
+func TestUpDown(t *testing.T) {
+	ctx := context.Background()
+	db := openDB(t)
+	m := newMigrator(t, db, testFS)
+
+	if m.Latest() != 3 {
+		t.Errorf("Latest() = %d, want 3", m.Latest())
+	}
+	checkState(t, db, m, 0)
+
+	ran, err := m.Up(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := versions(ran); !slices.Equal(got, []int64{1, 2, 3}) {
+		t.Errorf("Up ran %v, want [1 2 3]", got)
+	}
+	checkState(t, db, m, 3, "a", "b", "c")
+
+	// Up again is a no-op
+	if ran, err := m.Up(ctx); err != nil || len(ran) != 0 {
+		t.Errorf("second Up ran %v, %v; want nothing", versions(ran), err)
+	}
+
+	statuses, err := m.Status(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range statuses {
+		if !s.Applied || s.AppliedAt.IsZero() {
+			t.Errorf("Status of %d = applied %v at %v, want applied", s.Version, s.Applied, s.AppliedAt)
+		}
+	}
+
+	// 0003 has no down script
+	if _, err := m.Down(ctx); !errors.Is(err, ErrIrreversible) {
+		t.Errorf("Down from 3: err = %v, want ErrIrreversible", err)
+	}
+	checkState(t, db, m, 3, "a", "b", "c")
+}
+
+func TestTo(t *testing.T) {
+	ctx := context.Background()
+	db := openDB(t)
+	m := newMigrator(t, db, testFS)
+
+	ran, err := m.To(ctx, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := versions(ran); !slices.Equal(got, []int64{1, 2}) {
+		t.Errorf("To(2) ran %v, want [1 2]", got)
+	}
+	checkState(t, db, m, 2, "a", "b")
+
+	ran, err = m.Down(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := versions(ran); !slices.Equal(got, []int64{2}) {
+		t.Errorf("Down ran %v, want [2]", got)
+	}
+	checkState(t, db, m, 1, "a")
+
+	if _, err := m.To(ctx, 2); err != nil {
+		t.Fatal(err)
+	}
+	ran, err = m.To(ctx, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := versions(ran); !slices.Equal(got, []int64{2, 1}) {
+		t.Errorf("To(0) ran %v, want [2 1]", got)
+	}
+	checkState(t, db, m, 0)
+
+	// Down at version 0 is a no-op
+	if ran, err := m.Down(ctx); err != nil || len(ran) != 0 {
+		t.Errorf("Down at 0 ran %v, %v; want nothing", versions(ran), err)
+	}
+}
+
+func TestToUnknownVersion(t *testing.T) {
+	db := openDB(t)
+	m := newMigrator(t, db, testFS)
+
+	if _, err := m.To(context.Background(), 7); !errors.Is(err, ErrUnknownVersion) {
+		t.Errorf("To(7): err = %v, want ErrUnknownVersion", err)
+	}
+	checkState(t, db, m, 0)
+}
+
+func TestFailingScriptRollsBack(t *testing.T) {
+	ctx := context.Background()
+	db := openDB(t)
+
+	fsys := fstest.MapFS{
+		"0001_create_a.up.sql": testFS["0001_create_a.up.sql"],
+		"0002_create_b.up.sql": testFS["0002_create_b.up.sql"],
+		// The first statement succeeds, the second fails
+		"0003_broken.up.sql": {Data: []byte("CREATE TABLE c (id INTEGER PRIMARY KEY); INSERT INTO missing VALUES (1);")},
+	}
+	m := newMigrator(t, db, fsys)
+
+	if _, err := m.Up(ctx); err == nil {
+		t.Fatal("Up with a failing script succeeded")
+	}
+	// Nothing from the run is left behind, not even 0001 and 0002
+	checkState(t, db, m, 0)
+
+	// Migrations before the broken one can still be applied
+	if _, err := m.To(ctx, 2); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := m.Up(ctx); err == nil {
+		t.Fatal("Up with a failing script succeeded")
+	}
+	checkState(t, db, m, 2, "a", "b")
+}
+
+func TestNewRejectsBadFiles(t *testing.T) {
+	tests := map[string]fstest.MapFS{
+		"bad name":      {"create_a.up.sql": {Data: []byte("SELECT 1;")}},
+		"zero version":  {"0000_a.up.sql": {Data: []byte("SELECT 1;")}},
+		"missing up":    {"0001_a.down.sql": {Data: []byte("SELECT 1;")}},
+		"version clash": {"0001_a.up.sql": {Data: []byte("SELECT 1;")}, "0001_b.up.sql": {Data: []byte("SELECT 1;")}},
+	}
+	for name, fsys := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := New(nil, fsys); err == nil {
+				t.Error("New succeeded")
+			}
+		})
+	}
+}
@@ -0,0 +1,23 @@
+package sqlite
+
+import (
+	"embed"
+	"io/fs"
+
+	"github.com/SxxAq/go-api/internal/storage/migrate"
+)
+
+// migrationFiles holds the SQL migrations compiled into the binary.
+//
+//go:embed migrations/*.sql
+var migrationFiles embed.FS
+
+// Migrator returns a migrate.Migrator for the embedded schema migrations.
+func (s *Sqlite) Migrator() (*migrate.Migrator, error) {
+	files, err := fs.Sub(migrationFiles, "migrations")
+	if err != nil {
+		return nil, err
+	}
+
+	return migrate.New(s.Db, files)
+}
@@ -0,0 +1,7 @@
+-- IF NOT EXISTS adopts databases created before migrations were introduced.
+CREATE TABLE IF NOT EXISTS students (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	name TEXT NOT NULL,
+	email TEXT NOT NULL,
+	age INTEGER NOT NULL
+);
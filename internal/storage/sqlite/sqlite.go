@@ -0,0 +1,176 @@
+// Package sqlite implements storage.Storage on top of a SQLite database
+// file located at Config.StoragePath.
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/mattn/go-sqlite3" // Registers the "sqlite3" database/sql driver
+
+	"github.com/SxxAq/go-api/internal/apperr"
+	"github.com/SxxAq/go-api/internal/config"
+	"github.com/SxxAq/go-api/internal/storage"
+	"github.com/SxxAq/go-api/internal/types"
+)
+
+// Sqlite is a storage.Storage backed by a single SQLite database.
+type Sqlite struct {
+	Db *sql.DB
+}
+
+// Compile-time check that Sqlite satisfies the Storage interface.
+var _ storage.Storage = (*Sqlite)(nil)
+
+// New opens (creating if needed) the database at cfg.StoragePath with WAL
+// journaling and foreign keys enabled. The schema is managed separately
+// through Migrator.
+func New(cfg *config.Config) (*Sqlite, error) {
+	// 1. Make sure the directory holding the database exists
+	if dir := filepath.Dir(cfg.StoragePath); dir != "." {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return nil, fmt.Errorf("create storage directory: %w", err)
+		}
+	}
+
+	// 2. Open the database; pragmas are applied by the driver on every connection
+	dsn := fmt.Sprintf("file:%s?_journal_mode=WAL&_foreign_keys=on&_busy_timeout=5000", cfg.StoragePath)
+	db, err := sql.Open("sqlite3", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("open database: %w", err)
+	}
+
+	// 3. sql.Open is lazy, so ping to surface a bad path or locked file now
+	if err := db.Ping(); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("connect database: %w", err)
+	}
+
+	return &Sqlite{Db: db}, nil
+}
+
+// CreateStudent inserts a student and returns its generated id, or
+// storage.ErrConflict if it violates a uniqueness constraint.
+func (s *Sqlite) CreateStudent(ctx context.Context, name, email string, age int) (int64, error) {
+	res, err := s.Db.ExecContext(ctx,
+		"INSERT INTO students (name, email, age) VALUES (?, ?, ?)",
+		name, email, age,
+	)
+	if err != nil {
+		return 0, writeError("insert student", err)
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return 0, fmt.Errorf("insert student: %w", err)
+	}
+
+	return id, nil
+}
+
+// GetStudentById returns the student with the given id or storage.ErrNotFound.
+func (s *Sqlite) GetStudentById(ctx context.Context, id int64) (types.Student, error) {
+	var student types.Student
+
+	err := s.Db.QueryRowContext(ctx,
+		"SELECT id, name, email, age FROM students WHERE id = ?", id,
+	).Scan(&student.Id, &student.Name, &student.Email, &student.Age)
+	if errors.Is(err, sql.ErrNoRows) {
+		return types.Student{}, apperr.NotFound("student %d not found", id)
+	}
+	if err != nil {
+		return types.Student{}, fmt.Errorf("query student: %w", err)
+	}
+
+	return student, nil
+}
+
+// GetStudents returns every student ordered by id.
+func (s *Sqlite) GetStudents(ctx context.Context) ([]types.Student, error) {
+	rows, err := s.Db.QueryContext(ctx, "SELECT id, name, email, age FROM students ORDER BY id")
+	if err != nil {
+		return nil, fmt.Errorf("query students: %w", err)
+	}
+	defer rows.Close()
+
+	students := []types.Student{}
+	for rows.Next() {
+		var student types.Student
+		if err := rows.Scan(&student.Id, &student.Name, &student.Email, &student.Age); err != nil {
+			return nil, fmt.Errorf("scan student: %w", err)
+		}
+		students = append(students, student)
+	}
+
+	return students, rows.Err()
+}
+
+// UpdateStudent overwrites the stored student with the same id, or returns
+// storage.ErrNotFound or storage.ErrConflict.
+func (s *Sqlite) UpdateStudent(ctx context.Context, student types.Student) error {
+	res, err := s.Db.ExecContext(ctx,
+		"UPDATE students SET name = ?, email = ?, age = ? WHERE id = ?",
+		student.Name, student.Email, student.Age, student.Id,
+	)
+	if err != nil {
+		return writeError("update student", err)
+	}
+
+	return checkAffected(res, student.Id)
+}
+
+// DeleteStudent removes the student with the given id or returns
+// storage.ErrNotFound.
+func (s *Sqlite) DeleteStudent(ctx context.Context, id int64) error {
+	res, err := s.Db.ExecContext(ctx, "DELETE FROM students WHERE id = ?", id)
+	if err != nil {
+		return fmt.Errorf("delete student: %w", err)
+	}
+
+	return checkAffected(res, id)
+}
+
+// checkAffected turns a statement that touched no rows into storage.ErrNotFound.
+func checkAffected(res sql.Result, id int64) error {
+	n, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("rows affected: %w", err)
+	}
+	if n == 0 {
+		return apperr.NotFound("student %d not found", id)
+	}
+
+	return nil
+}
+
+// writeError wraps the error of a failed write, turning uniqueness
+// violations into storage.ErrConflict.
+func writeError(op string, err error) error {
+	var sqliteErr sqlite3.Error
+	if errors.As(err, &sqliteErr) &&
+		(sqliteErr.ExtendedCode == sqlite3.ErrConstraintUnique || sqliteErr.ExtendedCode == sqlite3.ErrConstraintPrimaryKey) {
+		return storage.ErrConflict.Wrap(err)
+	}
+
+	return fmt.Errorf("%s: %w", op, err)
+}
+
+// Ping reads the schema table, which unlike sql.DB.Ping touches the
+// database file and so also catches one that can no longer be read.
+func (s *Sqlite) Ping(ctx context.Context) error {
+	var n int
+	if err := s.Db.QueryRowContext(ctx, "SELECT count(*) FROM sqlite_master").Scan(&n); err != nil {
+		return fmt.Errorf("ping database: %w", err)
+	}
+
+	return nil
+}
+
+// Close closes the database.
+func (s *Sqlite) Close() error {
+	return s.Db.Close()
+}
@@ -0,0 +1,40 @@
+// Package storage defines the persistence contract used by the API.
+// Concrete implementations live in sub-packages such as storage/sqlite.
+package storage
+
+import (
+	"context"
+
+	"github.com/SxxAq/go-api/internal/apperr"
+	"github.com/SxxAq/go-api/internal/types"
+)
+
+// Implementations report failures the caller can act on as *apperr.Error
+// values; errors.Is matches them against these sentinels by code.
+var (
+	// ErrNotFound is returned when the requested record does not exist.
+	ErrNotFound = apperr.NotFound("record not found")
+	// ErrConflict is returned when a write clashes with existing data, such
+	// as a duplicate of a unique column.
+	ErrConflict = apperr.Conflict("record conflicts with existing data")
+)
+
+// Storage persists the API's resources.
+type Storage interface {
+	// CreateStudent inserts a student and returns its generated id, or
+	// ErrConflict.
+	CreateStudent(ctx context.Context, name, email string, age int) (int64, error)
+	// GetStudentById returns the student with the given id or ErrNotFound.
+	GetStudentById(ctx context.Context, id int64) (types.Student, error)
+	// GetStudents returns every student ordered by id.
+	GetStudents(ctx context.Context) ([]types.Student, error)
+	// UpdateStudent overwrites the stored student with the same id, or
+	// returns ErrNotFound or ErrConflict.
+	UpdateStudent(ctx context.Context, student types.Student) error
+	// DeleteStudent removes the student with the given id or returns ErrNotFound.
+	DeleteStudent(ctx context.Context, id int64) error
+	// Ping checks that the database is reachable and answers queries.
+	Ping(ctx context.Context) error
+	// Close releases the underlying connections.
+	Close() error
+}
@@ -0,0 +1,11 @@
+// Package types holds the domain models shared by the HTTP handlers and
+// the storage layer.
+package types
+
+// Student is the resource managed by the API.
+type Student struct {
+	Id    int64  `json:"id"`
+	Name  string `json:"name"`
+	Email string `json:"email"`
+	Age   int    `json:"age"`
+}